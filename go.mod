@@ -17,6 +17,7 @@ require (
 	k8s.io/client-go v0.28.0
 	k8s.io/klog/v2 v2.100.1
 	knative.dev/pkg v0.0.0-20230221145627-8efb3485adcf
+	sigs.k8s.io/yaml v1.3.0
 )
 
 require (
@@ -93,5 +94,4 @@ require (
 	k8s.io/utils v0.0.0-20230406110748-d93618cff8a2 // indirect
 	sigs.k8s.io/json v0.0.0-20221116044647-bc3834ca7abd // indirect
 	sigs.k8s.io/structured-merge-diff/v4 v4.2.3 // indirect
-	sigs.k8s.io/yaml v1.3.0 // indirect
 )
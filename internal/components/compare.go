@@ -0,0 +1,423 @@
+package components
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/status"
+	g "github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+// comparedRow is an aspect of the two runs being compared.
+type comparedRow struct {
+	name        string
+	left, right g.Node
+	changed     bool
+}
+
+// Compare renders the differences between two runs of the same
+// resource, along with the unified diffs of their resolved specs and
+// manifests.
+func Compare(td *model.TemplateData, specDiff, manifestDiff string) g.Node {
+	if td.Left == nil || td.Right == nil {
+		return g.Text("run not found")
+	}
+	return Div(
+		Class("h-screen"), StyleAttr("display: flex; flex-direction: column;"),
+		NavBar(td),
+		Div(
+			Class("container-fluid px-4 pb-4 flex flex-col gap-4"),
+			StyleAttr("overflow: auto;"),
+			H2(
+				Class("text-lg font-semibold"),
+				g.Text("Comparing "),
+				runLink(td, td.Left),
+				g.Text(" with "),
+				runLink(td, td.Right),
+			),
+			compareTable(td, "Run", overviewRows(td)),
+			compareTable(td, "Param", compareValues(td, "param-", runParams(td.Left), runParams(td.Right))),
+			compareTable(td, "Result", compareValues(td, "result-", runResults(td.Left), runResults(td.Right))),
+			compareTable(td, "Task", taskRows(td)),
+			compareTable(td, "Step", stepRows(td)),
+			diffSection(td, "Resolved spec", "spec-diff-", specDiff),
+			diffSection(td, "Manifest", "manifest-diff-", manifestDiff),
+		),
+	)
+}
+
+// runLink links to r, naming its namespace when the compared runs come
+// from different ones.
+func runLink(td *model.TemplateData, r *model.Run) g.Node {
+	obj := r.Object()
+	name := obj.GetName()
+	if td.Left.Object().GetNamespace() != td.Right.Object().GetNamespace() {
+		name = obj.GetNamespace() + "/" + name
+	}
+	return A(
+		Class("link link-info"),
+		Href(td.URLFor("list-w-details", obj.GetNamespace(), td.Resource, obj.GetName())),
+		g.Text(name),
+	)
+}
+
+func compareTable(td *model.TemplateData, column string, rows []comparedRow) g.Node {
+	if len(rows) == 0 {
+		return nil
+	}
+	return Table(
+		Class("table table-sm table-zebra table-pin-rows"),
+		THead(Tr(
+			Th(Class("w-64"), g.Text(column)),
+			Th(g.Text(td.Left.Object().GetName())),
+			Th(g.Text(td.Right.Object().GetName())),
+		)),
+		TBody(g.Map(rows, func(r comparedRow) g.Node {
+			return Tr(
+				Th(
+					Span(Class("select-all"), g.Text(r.name)),
+					g.If(r.changed, Span(Class("badge badge-sm badge-warning ms-2"), g.Text("changed"))),
+				),
+				Td(r.left),
+				Td(r.right),
+			)
+		})...),
+	)
+}
+
+// diffSection renders a unified diff, highlighted.
+func diffSection(td *model.TemplateData, title, prefix, diff string) g.Node {
+	return Div(
+		H3(Class("font-semibold mb-1"), g.Text(title)),
+		g.If(diff == "", P(Class("text-sm opacity-60"), g.Text("identical"))),
+		g.If(diff != "", Div(
+			Class("rounded-lg overflow-clip text-sm"),
+			highlighted(td, prefix, diff, "diff"),
+		)),
+	)
+}
+
+func absent() g.Node {
+	return Span(Class("opacity-60"), g.Text("-"))
+}
+
+func overviewRows(td *model.TemplateData) []comparedRow {
+	ls, lreason := runStatus(td.Left)
+	rs, rreason := runStatus(td.Right)
+	statusCell := func(s status.Status, reason string) g.Node {
+		return Span(
+			Class("inline-flex items-center gap-2"),
+			statusIcon(s, reason),
+			g.Text(s.Label()),
+			g.If(reason != "", Span(Class("badge badge-sm badge-outline"), g.Text(reason))),
+		)
+	}
+
+	lref, rref := runReference(td.Left), runReference(td.Right)
+	lstart, lend := runTimes(td.Left)
+	rstart, rend := runTimes(td.Right)
+	ld, lok := durationBetween(lstart, lend)
+	rd, rok := durationBetween(rstart, rend)
+
+	reference := "Pipeline"
+	if td.Left.PipelineRun == nil {
+		reference = "Task"
+	}
+	return []comparedRow{
+		{"Status", statusCell(ls, lreason), statusCell(rs, rreason), ls != rs || lreason != rreason},
+		{reference, g.Text(lref), g.Text(rref), lref != rref},
+		{"Started", g.Text(formatTime(lstart)), g.Text(formatTime(rstart)), false},
+		{"Duration", durationCell(ld, lok, 0, false), durationCell(rd, rok, ld, lok), false},
+	}
+}
+
+// durationCell renders d, along with how much it differs from base
+// when known.
+func durationCell(d time.Duration, ok bool, base time.Duration, baseOK bool) g.Node {
+	if !ok {
+		return absent()
+	}
+	delta := ""
+	if baseOK {
+		sign := "+"
+		diff := d - base
+		if diff < 0 {
+			sign, diff = "-", -diff
+		}
+		delta = sign + formatDuration(diff)
+	}
+	return Span(
+		g.Text(formatDuration(d)),
+		g.If(delta != "", Span(Class("badge badge-sm badge-ghost ms-2"), g.Text(delta))),
+	)
+}
+
+func durationBetween(start, end *metav1.Time) (time.Duration, bool) {
+	if start == nil || start.IsZero() {
+		return 0, false
+	}
+	return timeOr(end, time.Now()).Sub(start.Time), true
+}
+
+func runStatus(r *model.Run) (status.Status, string) {
+	if pr := r.PipelineRun; pr != nil {
+		return status.OfPipelineRun(pr), conditionReason(pr.Status.GetCondition(apis.ConditionSucceeded))
+	}
+	tr := r.TaskRuns[0]
+	return status.OfTaskRun(&tr.Status), conditionReason(tr.Status.GetCondition(apis.ConditionSucceeded))
+}
+
+func runReference(r *model.Run) string {
+	if r.PipelineRun != nil {
+		return pipelineReference(r.PipelineRun)
+	}
+	return taskReference(r.TaskRuns[0])
+}
+
+func runTimes(r *model.Run) (start, end *metav1.Time) {
+	if pr := r.PipelineRun; pr != nil {
+		return pr.Status.StartTime, pr.Status.CompletionTime
+	}
+	tr := r.TaskRuns[0]
+	return tr.Status.StartTime, tr.Status.CompletionTime
+}
+
+func runParams(r *model.Run) []namedValue {
+	params := r.TaskRuns[0].Spec.Params
+	if r.PipelineRun != nil {
+		params = r.PipelineRun.Spec.Params
+	}
+	values := make([]namedValue, 0, len(params))
+	for _, p := range params {
+		values = append(values, namedValue{p.Name, p.Value})
+	}
+	return values
+}
+
+func runResults(r *model.Run) []namedValue {
+	if r.PipelineRun == nil {
+		return taskRunResults(&r.TaskRuns[0].Status)
+	}
+	results := make([]namedValue, 0, len(r.PipelineRun.Status.PipelineResults))
+	for _, res := range r.PipelineRun.Status.PipelineResults {
+		results = append(results, namedValue{res.Name, res.Value})
+	}
+	return results
+}
+
+// compareValues pairs params or results by name, keeping the order of
+// the left run.
+func compareValues(td *model.TemplateData, prefix string, left, right []namedValue) []comparedRow {
+	type pair struct {
+		left, right *pipelinev1beta1.ParamValue
+	}
+	var names []string
+	pairs := map[string]*pair{}
+	get := func(name string) *pair {
+		p, ok := pairs[name]
+		if !ok {
+			p = &pair{}
+			pairs[name] = p
+			names = append(names, name)
+		}
+		return p
+	}
+	for i := range left {
+		get(left[i].Name).left = &left[i].Value
+	}
+	for i := range right {
+		get(right[i].Name).right = &right[i].Value
+	}
+
+	cell := func(side, name string, v *pipelinev1beta1.ParamValue) g.Node {
+		if v == nil {
+			return absent()
+		}
+		return value(td, side+"-"+prefix+name+"-", *v)
+	}
+	rows := make([]comparedRow, 0, len(names))
+	for _, name := range names {
+		p := pairs[name]
+		rows = append(rows, comparedRow{
+			name:    name,
+			left:    cell("left", name, p.left),
+			right:   cell("right", name, p.right),
+			changed: !reflect.DeepEqual(p.left, p.right),
+		})
+	}
+	return rows
+}
+
+// taskOutcome summarizes how a pipelineTask went in a pipelineRun.
+type taskOutcome struct {
+	status   status.Status
+	reason   string
+	duration time.Duration
+	started  bool
+	runs     int
+}
+
+// taskOutcomes returns the outcome of every pipelineTask of r, along
+// with their names in the order they ran.
+func taskOutcomes(r *model.Run) ([]string, map[string]*taskOutcome) {
+	var names []string
+	outcomes := map[string]*taskOutcome{}
+	for _, grp := range groupTaskRuns(r.TaskRuns) {
+		statuses := make([]status.Status, 0, len(grp.taskRuns))
+		var start, end *metav1.Time
+		for _, tr := range grp.taskRuns {
+			statuses = append(statuses, status.OfTaskRun(&tr.Status))
+			if st := tr.Status.StartTime; st != nil && (start == nil || st.Before(start)) {
+				start = st
+			}
+			if ct := tr.Status.CompletionTime; ct != nil && (end == nil || end.Before(ct)) {
+				end = ct
+			}
+		}
+		o := &taskOutcome{status: status.Aggregate(statuses...), runs: len(grp.taskRuns)}
+		if len(grp.taskRuns) == 1 {
+			o.reason = conditionReason(grp.taskRuns[0].Status.GetCondition(apis.ConditionSucceeded))
+		}
+		o.duration, o.started = durationBetween(start, end)
+		names = append(names, grp.name)
+		outcomes[grp.name] = o
+	}
+	for _, st := range r.PipelineRun.Status.SkippedTasks {
+		names = append(names, st.Name)
+		outcomes[st.Name] = &taskOutcome{status: status.Skipped, reason: string(st.Reason)}
+	}
+	return names, outcomes
+}
+
+// taskRows compares the status and duration of the pipelineTasks of
+// two pipelineRuns.
+func taskRows(td *model.TemplateData) []comparedRow {
+	if td.Left.PipelineRun == nil || td.Right.PipelineRun == nil {
+		return nil
+	}
+	lnames, left := taskOutcomes(td.Left)
+	rnames, right := taskOutcomes(td.Right)
+
+	cell := func(o, base *taskOutcome) g.Node {
+		if o == nil {
+			return absent()
+		}
+		baseOK := base != nil && base.started
+		var baseDuration time.Duration
+		if baseOK {
+			baseDuration = base.duration
+		}
+		return Span(
+			Class("inline-flex items-center gap-2"),
+			statusIcon(o.status, o.reason),
+			g.If(o.status != status.Skipped, durationCell(o.duration, o.started, baseDuration, baseOK)),
+			g.If(o.runs > 1, Span(Class("badge badge-sm badge-ghost"), g.Text(pluralize(o.runs, "run")))),
+			g.If(o.status == status.Skipped && o.reason != "", Span(
+				Class("badge badge-sm badge-outline"),
+				g.Text(o.reason),
+			)),
+		)
+	}
+
+	var rows []comparedRow
+	for _, name := range append(lnames, rnames...) {
+		l, r := left[name], right[name]
+		if l == nil && r == nil {
+			// already compared
+			continue
+		}
+		rows = append(rows, comparedRow{
+			name:    name,
+			left:    cell(l, nil),
+			right:   cell(r, l),
+			changed: l == nil || r == nil || l.status != r.status,
+		})
+		delete(left, name)
+		delete(right, name)
+	}
+	return rows
+}
+
+// stepImage is the image a step ran, and the digest it resolved to.
+type stepImage struct {
+	image, imageID string
+}
+
+// stepImages returns the images of the steps of r, keyed by their
+// pipelineTask and step name, along with the keys in the order they ran.
+func stepImages(r *model.Run) ([]string, map[string]stepImage) {
+	var keys []string
+	images := map[string]stepImage{}
+	for _, grp := range groupTaskRuns(r.TaskRuns) {
+		for i, tr := range grp.taskRuns {
+			prefix := ""
+			if r.PipelineRun != nil {
+				prefix = grp.name
+				if len(grp.taskRuns) > 1 {
+					prefix += "[" + strconv.Itoa(i) + "]"
+				}
+				prefix += " / "
+			}
+			specImages := map[string]string{}
+			if spec := tr.Status.TaskSpec; spec != nil {
+				for _, s := range spec.Steps {
+					specImages[s.Name] = s.Image
+				}
+			}
+			for _, ss := range tr.Status.Steps {
+				key := prefix + ss.Name
+				keys = append(keys, key)
+				images[key] = stepImage{specImages[ss.Name], ss.ImageID}
+			}
+		}
+	}
+	return keys, images
+}
+
+// stepRows compares the images and digests the steps of two runs ran.
+func stepRows(td *model.TemplateData) []comparedRow {
+	lkeys, left := stepImages(td.Left)
+	rkeys, right := stepImages(td.Right)
+
+	cell := func(img stepImage, ok bool) g.Node {
+		if !ok {
+			return absent()
+		}
+		digest := img.imageID
+		if _, d, found := strings.Cut(digest, "@"); found {
+			digest = d
+		}
+		return Div(
+			Div(Class("select-all break-all"), g.Text(img.image)),
+			g.If(digest != "", Div(
+				Class("text-xs font-mono opacity-60 select-all break-all"),
+				g.Text(digest),
+			)),
+		)
+	}
+
+	var rows []comparedRow
+	seen := map[string]bool{}
+	for _, key := range append(lkeys, rkeys...) {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		l, lok := left[key]
+		r, rok := right[key]
+		rows = append(rows, comparedRow{
+			name:    key,
+			left:    cell(l, lok),
+			right:   cell(r, rok),
+			changed: lok != rok || l != r,
+		})
+	}
+	return rows
+}
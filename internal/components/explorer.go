@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/syntax"
 	g "github.com/maragudk/gomponents"
 	htmx "github.com/maragudk/gomponents-htmx"
 	c "github.com/maragudk/gomponents/components"
@@ -47,6 +48,41 @@ func NavBar(td *model.TemplateData) g.Node {
 				})),
 			),
 		),
+		Div(
+			Class("navbar-end gap-4 pe-2"),
+			Select(
+				Name(model.SyntaxStyleCookie),
+				Class("select select-sm select-bordered"),
+				TitleAttr("Syntax style"),
+				AutoComplete("off"),
+				htmx.Get(td.URLFor("preferences")),
+				htmx.Swap("none"),
+				g.Group(g.Map(syntax.Styles(), func(style string) g.Node {
+					return Option(g.If(style == td.SyntaxStyle, Selected()), g.Text(style))
+				})),
+			),
+			themeToggle(td),
+		),
+	)
+}
+
+// themeToggle switches between the light and dark themes.
+func themeToggle(td *model.TemplateData) g.Node {
+	next := model.LightTheme
+	if td.Theme == model.LightTheme {
+		next = model.DarkTheme
+	}
+	return Label(
+		Class("label cursor-pointer gap-2"),
+		Span(Class("label-text"), g.Text("Light")),
+		Input(
+			Type("checkbox"),
+			Class("toggle toggle-sm"),
+			g.If(td.Theme == model.LightTheme, g.Attr("checked")),
+			AutoComplete("off"),
+			htmx.Get(appendQuery(td.URLFor("preferences"), model.ThemeCookie+"="+next)),
+			htmx.Swap("none"),
+		),
 	)
 }
 
@@ -117,6 +153,17 @@ func ExplorerList(td *model.TemplateData) g.Node {
 			htmx.Include("#search"),
 			htmx.Trigger("revealed"),
 			htmx.Swap("innerHTML"),
+			Th(
+				Button(
+					Class("btn btn-xs btn-ghost"),
+					TitleAttr("Compare the two selected runs"),
+					htmx.Get(td.URLFor("compare-selection", td.Resource)),
+					htmx.Include("#items [name='compare']"),
+					htmx.Target("#compare-error"),
+					g.Text("Compare"),
+				),
+				Div(ID("compare-error"), Class("text-xs text-error font-normal")),
+			),
 			Th(g.Text("Name")),
 			Th(g.Text("Age")),
 		)),
@@ -124,30 +171,6 @@ func ExplorerList(td *model.TemplateData) g.Node {
 	)
 }
 
-func iconFor(status string) g.Node {
-	switch status {
-	case "Failed":
-		return g.Raw(`
-            <svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" fill="currentColor" class="text-error bi bi-x-circle-fill" viewBox="0 0 16 16">
-                <path d="M16 8A8 8 0 1 1 0 8a8 8 0 0 1 16 0zM5.354 4.646a.5.5 0 1 0-.708.708L7.293 8l-2.647 2.646a.5.5 0 0 0 .708.708L8 8.707l2.646 2.647a.5.5 0 0 0 .708-.708L8.707 8l2.647-2.646a.5.5 0 0 0-.708-.708L8 7.293 5.354 4.646z"/>
-            </svg>
-        `)
-	case "Succeeded":
-		return g.Raw(`
-            <svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" fill="currentColor" class="text-success bi bi-check-circle-fill" viewBox="0 0 16 16">
-                <path d="M16 8A8 8 0 1 1 0 8a8 8 0 0 1 16 0zm-3.97-3.03a.75.75 0 0 0-1.08.022L7.477 9.417 5.384 7.323a.75.75 0 0 0-1.06 1.06L6.97 11.03a.75.75 0 0 0 1.079-.02l3.992-4.99a.75.75 0 0 0-.01-1.05z"/>
-            </svg>
-        `)
-	case "Running":
-		return g.Raw(`
-            <svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" fill="currentColor" class="text-warning bi bi-circle-fill" viewBox="0 0 16 16">
-                <circle cx="8" cy="8" r="8"/>
-            </svg>
-        `)
-	}
-	return g.Text("")
-}
-
 func ExplorerListItems(sr model.SearchResults) []g.Node {
 	return g.Map(sr.Items, func(it model.SearchItem) g.Node {
 		return Tr(
@@ -159,6 +182,13 @@ func ExplorerListItems(sr model.SearchResults) []g.Node {
 					htmx.Swap("afterend"),
 				}),
 			),
+			Td(Input(
+				Type("checkbox"),
+				Class("checkbox checkbox-sm"),
+				Name("compare"),
+				Value(it.Namespace+"/"+it.Name),
+				AutoComplete("off"),
+			)),
 			Td(
 				A(
 					Href("#"),
@@ -179,7 +209,7 @@ func ExplorerListItems(sr model.SearchResults) []g.Node {
 					)),
 					Span(
 						Class("pe-2"),
-						iconFor(it.Status),
+						statusIcon(it.Status, it.Reason),
 					),
 					g.Text(it.Name),
 				),
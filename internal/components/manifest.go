@@ -0,0 +1,73 @@
+package components
+
+import (
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	g "github.com/maragudk/gomponents"
+	htmx "github.com/maragudk/gomponents-htmx"
+	c "github.com/maragudk/gomponents/components"
+	. "github.com/maragudk/gomponents/html"
+)
+
+// manifestView is the pipelineRun view rendering its manifest.
+const manifestView = "manifest"
+
+// StepManifest renders the manifest of the browsed taskRun, or of the
+// task spec it resolved to.
+func StepManifest(td *model.TemplateData, manifest g.Node) g.Node {
+	return RGroup(
+		StepDetailsTabs(td, "manifest", true),
+		manifestToggles(
+			td,
+			withState(td, td.URLFor("manifest", td.Namespace, td.TaskRun.GetName(), td.Step)),
+			"#step-details-content",
+			"TaskRun",
+			"Task spec",
+		),
+		manifest,
+	)
+}
+
+// PipelineRunManifest renders the manifest of the browsed pipelineRun,
+// or of the pipeline spec it resolved to.
+func PipelineRunManifest(td *model.TemplateData, manifest g.Node) g.Node {
+	return RGroup(
+		PipelineRunViewTabs(td, manifestView, true),
+		manifestToggles(
+			td,
+			td.URLFor("pipeline-manifest", td.Namespace, td.PipelineRun.GetName()),
+			"#taskrun-details",
+			"PipelineRun",
+			"Pipeline spec",
+		),
+		manifest,
+	)
+}
+
+// manifestToggles switches between the documents a manifest view
+// renders, and between formats.
+func manifestToggles(td *model.TemplateData, u, target, runLabel, specLabel string) g.Node {
+	button := func(document, format, label string, active bool) g.Node {
+		return Button(
+			c.Classes{
+				"btn btn-sm join-item": true,
+				"btn-active":           active,
+			},
+			htmx.Get(appendQuery(u, "manifest="+document+"&format="+format)),
+			htmx.Target(target),
+			g.Text(label),
+		)
+	}
+	return Div(
+		Class("flex gap-4 mb-2"),
+		Div(
+			Class("join"),
+			button(model.RunManifest, td.Format, runLabel, td.Manifest == model.RunManifest),
+			button(model.SpecManifest, td.Format, specLabel, td.Manifest == model.SpecManifest),
+		),
+		Div(
+			Class("join"),
+			button(td.Manifest, model.YAMLFormat, "YAML", td.Format == model.YAMLFormat),
+			button(td.Manifest, model.JSONFormat, "JSON", td.Format == model.JSONFormat),
+		),
+	)
+}
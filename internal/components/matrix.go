@@ -0,0 +1,106 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/status"
+	g "github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+// taskRunGroup is a pipelineTask along with the taskRuns it fanned out
+// to, which are many for matrixed pipelineTasks.
+type taskRunGroup struct {
+	name     string
+	taskRuns []*pipelinev1beta1.TaskRun
+}
+
+// groupTaskRuns groups taskRuns by their pipelineTask, keeping the
+// order in which pipelineTasks first appear.
+func groupTaskRuns(trs []*pipelinev1beta1.TaskRun) []*taskRunGroup {
+	var groups []*taskRunGroup
+	byName := map[string]*taskRunGroup{}
+	for _, tr := range trs {
+		if tr == nil {
+			continue
+		}
+		name := tr.GetLabels()[pipeline.PipelineTaskLabelKey]
+		if grp, ok := byName[name]; ok && name != "" {
+			grp.taskRuns = append(grp.taskRuns, tr)
+			continue
+		}
+		grp := &taskRunGroup{name: taskDisplayName(tr), taskRuns: []*pipelinev1beta1.TaskRun{tr}}
+		groups = append(groups, grp)
+		byName[name] = grp
+	}
+	return groups
+}
+
+func taskRunGroupNode(td *model.TemplateData) renders[*taskRunGroup] {
+	return func(grp *taskRunGroup) g.Node {
+		if len(grp.taskRuns) == 1 {
+			return taskRun(td, false)(grp.taskRuns[0])
+		}
+
+		statuses := make([]status.Status, 0, len(grp.taskRuns))
+		open := false
+		for _, tr := range grp.taskRuns {
+			statuses = append(statuses, status.OfTaskRun(&tr.Status))
+			open = open || (td.TaskRun != nil && td.TaskRun.GetName() == tr.GetName())
+		}
+
+		return Li(
+			Details(
+				g.If(open, g.Attr("open")),
+				Summary(
+					Class("font-semibold"),
+					statusIcon(status.Aggregate(statuses...), ""),
+					g.Text(grp.name),
+					Span(
+						Class("badge badge-sm badge-ghost"),
+						g.Text(pluralize(len(grp.taskRuns), "run")),
+					),
+				),
+				Ul(g.Group(g.Map(grp.taskRuns, taskRun(td, false)))),
+			),
+		)
+	}
+}
+
+// matrixCombination describes the matrix params a taskRun was fanned
+// out with, or returns an empty string if its pipelineTask has no
+// matrix.
+func matrixCombination(td *model.TemplateData, tr *pipelinev1beta1.TaskRun) string {
+	if td.PipelineRun == nil || td.PipelineRun.Status.PipelineSpec == nil {
+		return ""
+	}
+	spec := td.PipelineRun.Status.PipelineSpec
+	name := tr.GetLabels()[pipeline.PipelineTaskLabelKey]
+
+	var matrix *pipelinev1beta1.Matrix
+	for _, pt := range append(append([]pipelinev1beta1.PipelineTask{}, spec.Tasks...), spec.Finally...) {
+		if pt.Name == name {
+			matrix = pt.Matrix
+		}
+	}
+	if matrix == nil {
+		return ""
+	}
+	names := matrix.GetAllParams().ExtractNames()
+
+	var parts []string
+	for _, p := range tr.Spec.Params {
+		if !names.Has(p.Name) {
+			continue
+		}
+		v := p.Value.StringVal
+		if p.Value.Type == pipelinev1beta1.ParamTypeArray {
+			v = "[" + strings.Join(p.Value.ArrayVal, ", ") + "]"
+		}
+		parts = append(parts, p.Name+"="+v)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,93 @@
+package components
+
+import (
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/status"
+	g "github.com/maragudk/gomponents"
+	htmx "github.com/maragudk/gomponents-htmx"
+	. "github.com/maragudk/gomponents/html"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"knative.dev/pkg/apis"
+)
+
+// childPipelineRuns renders the pipelineRuns started by the browsed
+// pipelineRun in the tasks panel.
+func childPipelineRuns(td *model.TemplateData) []g.Node {
+	if len(td.ChildPipelineRuns) == 0 {
+		return nil
+	}
+	taskNames := map[string]string{}
+	for _, cr := range td.PipelineRun.Status.ChildReferences {
+		taskNames[cr.Name] = cr.PipelineTaskName
+	}
+	return g.Map(td.ChildPipelineRuns, func(pr *pipelinev1beta1.PipelineRun) g.Node {
+		name := taskNames[pr.GetName()]
+		if name == "" {
+			name = pr.GetLabels()[pipeline.PipelineTaskLabelKey]
+		}
+		return childPipelineRun(td, name, pr)
+	})
+}
+
+// childPipelineRun renders an expandable entry for a child pipelineRun,
+// loading its tasks and own children once expanded.
+func childPipelineRun(td *model.TemplateData, name string, pr *pipelinev1beta1.PipelineRun) g.Node {
+	if name == "" {
+		name = pr.GetName()
+	}
+	return Li(
+		Details(
+			htmx.Get(td.URLFor("pipeline-children", pr.GetNamespace(), pr.GetName())),
+			htmx.Trigger("toggle once"),
+			htmx.Target("find ul"),
+			htmx.Swap("innerHTML"),
+			Summary(
+				Class("font-semibold"),
+				statusIcon(
+					status.OfPipelineRun(pr),
+					conditionReason(pr.Status.GetCondition(apis.ConditionSucceeded)),
+				),
+				g.Text(name),
+				Span(Class("badge badge-sm badge-ghost"), g.Text("pipelineRun")),
+			),
+			Ul(Li(Span(Class("loading loading-dots loading-sm")))),
+		),
+	)
+}
+
+// PipelineChildren renders the entries nested under a child pipelineRun
+// in the tasks panel of its parent. They link to the page of the child,
+// where its steps can be browsed.
+func PipelineChildren(td *model.TemplateData) g.Node {
+	if td.PipelineRun == nil {
+		return g.Text("pipelineRun not found")
+	}
+	nodes := []g.Node{
+		Li(A(
+			Class("link link-info"),
+			Href(td.URLFor(
+				"list-w-details",
+				td.Namespace,
+				"pipelineruns",
+				td.PipelineRun.GetName(),
+			)),
+			g.Text("Open "+td.PipelineRun.GetName()),
+		)),
+	}
+	for _, tr := range td.TaskRuns {
+		label := taskDisplayName(tr)
+		if combination := matrixCombination(td, tr); combination != "" {
+			label += " (" + combination + ")"
+		}
+		nodes = append(nodes, Li(A(
+			Href(taskRunURL(td, tr.GetName())),
+			statusIcon(
+				status.OfTaskRun(&tr.Status),
+				conditionReason(tr.Status.GetCondition(apis.ConditionSucceeded)),
+			),
+			g.Text(label),
+		)))
+	}
+	return RGroup(append(nodes, childPipelineRuns(td)...)...)
+}
@@ -0,0 +1,242 @@
+package components
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/dag"
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/status"
+	g "github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+const (
+	graphNodeWidth  = 180
+	graphNodeHeight = 40
+	graphColumnGap  = 70
+	graphRowGap     = 24
+	graphPadding    = 24
+	graphMaxLabel   = 22
+)
+
+type graphTask struct {
+	taskRun *pipelinev1beta1.TaskRun
+	status  status.Status
+	skipped *pipelinev1beta1.SkippedTask
+	finally bool
+}
+
+// PipelineGraph renders the tasks of a pipelineRun as a DAG built from
+// their runAfter and result reference dependencies.
+func PipelineGraph(td *model.TemplateData) g.Node {
+	if td.PipelineRun == nil {
+		return g.Text("pipelineRun not found")
+	}
+	spec := td.PipelineRun.Status.PipelineSpec
+	if spec == nil {
+		return RGroup(
+			PipelineRunViewTabs(td, "graph", true),
+			g.Text("the pipeline spec has not been resolved yet"),
+		)
+	}
+
+	tasks := map[string]*graphTask{}
+	var nodes []dag.Node
+	add := func(pt pipelinev1beta1.PipelineTask, finally bool) {
+		tasks[pt.Name] = &graphTask{finally: finally}
+		nodes = append(nodes, dag.Node{
+			Name:  pt.Name,
+			Deps:  pt.Deps(),
+			Final: finally,
+		})
+	}
+	for _, pt := range spec.Tasks {
+		add(pt, false)
+	}
+	for _, pt := range spec.Finally {
+		add(pt, true)
+	}
+	if len(nodes) == 0 {
+		return RGroup(
+			PipelineRunViewTabs(td, "graph", true),
+			g.Text("the pipeline has no tasks"),
+		)
+	}
+
+	// matrixed tasks fan out to many taskRuns, summed up in a status
+	for _, grp := range groupTaskRuns(td.TaskRuns) {
+		t, ok := tasks[grp.taskRuns[0].GetLabels()[pipeline.PipelineTaskLabelKey]]
+		if !ok {
+			continue
+		}
+		statuses := make([]status.Status, 0, len(grp.taskRuns))
+		for _, tr := range grp.taskRuns {
+			statuses = append(statuses, status.OfTaskRun(&tr.Status))
+		}
+		t.taskRun = grp.taskRuns[0]
+		t.status = status.Aggregate(statuses...)
+	}
+	for i, st := range td.PipelineRun.Status.SkippedTasks {
+		if t, ok := tasks[st.Name]; ok {
+			t.skipped = &td.PipelineRun.Status.SkippedTasks[i]
+			t.status = status.Skipped
+		}
+	}
+
+	layout := dag.Layered(nodes)
+
+	x := func(col int) int {
+		return graphPadding + col*(graphNodeWidth+graphColumnGap)
+	}
+	y := func(row int) int {
+		return graphPadding + row*(graphNodeHeight+graphRowGap)
+	}
+	width := x(layout.Columns) - graphColumnGap + graphPadding
+	height := y(layout.Rows) - graphRowGap + graphPadding
+
+	placed := map[string]dag.Placement{}
+	finallyColumn := -1
+	for _, p := range layout.Nodes {
+		placed[p.Name] = p
+		if p.Final && (finallyColumn < 0 || p.Column < finallyColumn) {
+			finallyColumn = p.Column
+		}
+	}
+
+	var children []g.Node
+
+	if finallyColumn >= 0 {
+		sepX := x(finallyColumn) - graphColumnGap/2
+		children = append(children,
+			g.El("line",
+				g.Attr("x1", fmt.Sprint(sepX)), g.Attr("y1", "4"),
+				g.Attr("x2", fmt.Sprint(sepX)), g.Attr("y2", fmt.Sprint(height-4)),
+				g.Attr("stroke", "currentColor"),
+				g.Attr("stroke-opacity", "0.4"),
+				g.Attr("stroke-dasharray", "4 4"),
+			),
+			g.El("text",
+				g.Attr("x", fmt.Sprint(x(finallyColumn))), g.Attr("y", "14"),
+				g.Attr("fill", "currentColor"),
+				g.Attr("fill-opacity", "0.6"),
+				g.Attr("font-size", "11"),
+				g.Text("finally"),
+			),
+		)
+	}
+
+	for _, e := range layout.Edges {
+		from, to := placed[e.From], placed[e.To]
+		x1, y1 := x(from.Column)+graphNodeWidth, y(from.Row)+graphNodeHeight/2
+		x2, y2 := x(to.Column), y(to.Row)+graphNodeHeight/2
+		mid := (x1 + x2) / 2
+		children = append(children, g.El("path",
+			g.Attr("d", fmt.Sprintf("M%d %d C%d %d, %d %d, %d %d", x1, y1, mid, y1, mid, y2, x2, y2)),
+			g.Attr("fill", "none"),
+			g.Attr("stroke", "currentColor"),
+			g.Attr("stroke-opacity", "0.5"),
+			g.Attr("marker-end", "url(#graph-arrow)"),
+		))
+	}
+
+	for _, p := range layout.Nodes {
+		children = append(children, graphNode(td, p, tasks[p.Name], x(p.Column), y(p.Row)))
+	}
+
+	return RGroup(
+		PipelineRunViewTabs(td, "graph", true),
+		Div(
+			Class("overflow-auto rounded-lg bg-base-200"),
+			g.El("svg",
+				g.Attr("xmlns", "http://www.w3.org/2000/svg"),
+				g.Attr("width", fmt.Sprint(width)),
+				g.Attr("height", fmt.Sprint(height)),
+				g.Attr("viewBox", fmt.Sprintf("0 0 %d %d", width, height)),
+				g.El("defs", g.El("marker",
+					ID("graph-arrow"),
+					g.Attr("viewBox", "0 0 10 10"),
+					g.Attr("refX", "10"), g.Attr("refY", "5"),
+					g.Attr("markerWidth", "6"), g.Attr("markerHeight", "6"),
+					g.Attr("orient", "auto-start-reverse"),
+					g.El("path",
+						g.Attr("d", "M 0 0 L 10 5 L 0 10 z"),
+						g.Attr("fill", "currentColor"),
+						g.Attr("fill-opacity", "0.5"),
+					),
+				)),
+				g.Group(children),
+			),
+		),
+	)
+}
+
+func graphNode(td *model.TemplateData, p dag.Placement, t *graphTask, x, y int) g.Node {
+	stroke, fill := "currentColor", "transparent"
+	if v := statusColor(t.status); v != "" {
+		stroke = "hsl(var(" + v + "))"
+		fill = "hsl(var(" + v + ") / 0.15)"
+	}
+
+	label := "Not started"
+	if t.taskRun != nil || t.skipped != nil {
+		label = t.status.Label()
+	}
+	tooltip := p.Name + ": " + label
+	if t.skipped != nil {
+		tooltip += " (" + string(t.skipped.Reason) + ")"
+	}
+	if t.finally {
+		tooltip += ", finally task"
+	}
+
+	name := p.Name
+	if utf8.RuneCountInString(name) > graphMaxLabel {
+		name, _ = truncateRunes(name, graphMaxLabel-1)
+		name += "…"
+	}
+
+	radius := "6"
+	if t.finally {
+		radius = fmt.Sprint(graphNodeHeight / 2)
+	}
+
+	node := g.El("g",
+		g.If(t.skipped != nil || t.taskRun == nil, g.Attr("opacity", "0.5")),
+		g.El("title", g.Text(tooltip)),
+		g.El("rect",
+			g.Attr("x", fmt.Sprint(x)), g.Attr("y", fmt.Sprint(y)),
+			g.Attr("width", fmt.Sprint(graphNodeWidth)),
+			g.Attr("height", fmt.Sprint(graphNodeHeight)),
+			g.Attr("rx", radius),
+			g.Attr("fill", fill),
+			g.Attr("stroke", stroke),
+			g.Attr("stroke-width", "1.5"),
+			g.If(t.skipped != nil, g.Attr("stroke-dasharray", "5 3")),
+		),
+		g.El("text",
+			g.Attr("x", fmt.Sprint(x+graphNodeWidth/2)),
+			g.Attr("y", fmt.Sprint(y+graphNodeHeight/2)),
+			g.Attr("text-anchor", "middle"),
+			g.Attr("dominant-baseline", "central"),
+			g.Attr("fill", "currentColor"),
+			g.Attr("font-size", "13"),
+			g.If(t.skipped != nil, g.Attr("text-decoration", "line-through")),
+			g.Text(name),
+		),
+	)
+
+	if t.taskRun == nil {
+		return node
+	}
+	href := taskRunURL(td, t.taskRun.GetName())
+	if steps := t.taskRun.Status.Steps; len(steps) > 0 {
+		href = stepURL(td, t.taskRun.GetName(), steps[0].Name)
+	}
+	return g.El("a",
+		g.Attr("href", href),
+		node,
+	)
+}
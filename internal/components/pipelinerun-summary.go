@@ -0,0 +1,188 @@
+package components
+
+import (
+	"time"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/status"
+	g "github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+type field struct {
+	name  string
+	value g.Node
+}
+
+// PipelineRunSummary renders the details of the pipelineRun itself,
+// rather than the ones of its taskRuns.
+func PipelineRunSummary(td *model.TemplateData) g.Node {
+	if td.PipelineRun == nil {
+		return g.Text("pipelineRun not found")
+	}
+	pr := td.PipelineRun
+
+	results := make([]namedValue, 0, len(pr.Status.PipelineResults))
+	for _, r := range pr.Status.PipelineResults {
+		results = append(results, namedValue{r.Name, r.Value})
+	}
+
+	return RGroup(
+		PipelineRunViewTabs(td, "summary", true),
+		g.If(len(td.Ancestry) > 0, breadcrumbsNode(pipelineBreadcrumbs(td))),
+		Div(
+			H2(
+				Class("text-lg font-semibold inline-flex items-center gap-2 mb-2"),
+				statusIcon(status.OfPipelineRun(pr), conditionReason(pr.Status.GetCondition(apis.ConditionSucceeded))),
+				g.Text(pr.GetName()),
+			),
+			g.If(len(results) == 0, P(
+				Class("text-sm opacity-60"),
+				g.Text("this pipelineRun has no results"),
+			)),
+			resultsTable(td, "pr-result-", results),
+		),
+	)
+}
+
+// PipelineRunHeader renders the pipelineRun above its taskRuns, with
+// its condition, timing and the params and workspaces it runs with.
+func PipelineRunHeader(td *model.TemplateData) g.Node {
+	if td.PipelineRun == nil {
+		return nil
+	}
+	pr := td.PipelineRun
+	cond := pr.Status.GetCondition(apis.ConditionSucceeded)
+	var reason, message string
+	if cond != nil {
+		reason, message = cond.Reason, cond.Message
+	}
+
+	fields := []field{
+		{"Pipeline", g.Text(pipelineReference(pr))},
+		{"Service account", g.Text(pr.Spec.ServiceAccountName)},
+		{"Started", g.Text(formatTime(pr.Status.StartTime))},
+		{"Completed", g.Text(formatTime(pr.Status.CompletionTime))},
+	}
+	if st := pr.Status.StartTime; st != nil {
+		end := timeOr(pr.Status.CompletionTime, time.Now())
+		fields = append(fields, field{"Duration", g.Text(formatDuration(end.Sub(st.Time)))})
+	}
+	if t := pr.Spec.Timeouts; t != nil {
+		fields = append(fields,
+			field{"Pipeline timeout", g.Text(formatTimeout(t.Pipeline))},
+			field{"Tasks timeout", g.Text(formatTimeout(t.Tasks))},
+			field{"Finally timeout", g.Text(formatTimeout(t.Finally))},
+		)
+	} else if pr.Spec.Timeout != nil {
+		fields = append(fields, field{"Timeout", g.Text(formatTimeout(pr.Spec.Timeout))})
+	}
+
+	var resolver pipelinev1beta1.ResolverRef
+	var bundle string
+	if ref := pr.Spec.PipelineRef; ref != nil {
+		resolver, bundle = ref.ResolverRef, ref.Bundle
+	}
+	fields = append(fields, sourceFields(td, "pr-", resolver, bundle, pr.Status.Provenance)...)
+
+	params := make([]namedValue, 0, len(pr.Spec.Params))
+	for _, p := range pr.Spec.Params {
+		params = append(params, namedValue{p.Name, p.Value})
+	}
+
+	section := func(title string, content g.Node) g.Node {
+		return Details(
+			Summary(Class("cursor-pointer font-semibold text-sm"), g.Text(title)),
+			content,
+		)
+	}
+
+	return Div(
+		ID("pipelinerun-summary"),
+		Class("mx-3 mt-3 p-3 rounded-box bg-base-200 flex flex-col gap-2"),
+		Div(
+			H2(
+				Class("text-lg font-semibold inline-flex items-center gap-2"),
+				statusIcon(status.OfPipelineRun(pr), reason),
+				g.Text(pr.GetName()),
+				g.If(reason != "", Span(
+					Class("badge badge-outline"),
+					g.Text(reason),
+				)),
+			),
+			g.If(message != "", P(
+				Class("text-sm opacity-80 select-all"),
+				g.Text(message),
+			)),
+		),
+		Div(
+			Class("flex flex-wrap gap-x-6 gap-y-1 text-sm"),
+			g.Group(g.Map(fields, func(f field) g.Node {
+				return Span(
+					Span(Class("opacity-60 me-1"), g.Text(f.name+":")),
+					f.value,
+				)
+			})),
+		),
+		g.If(len(params) > 0, section(
+			pluralize(len(params), "param"),
+			paramsTable(td, "pr-param-", params),
+		)),
+		section("Workspaces", pipelineRunWorkspaces(td)),
+	)
+}
+
+// pipelineReference describes the pipeline pr runs.
+func pipelineReference(pr *pipelinev1beta1.PipelineRun) string {
+	switch ref := pr.Spec.PipelineRef; {
+	case ref != nil && ref.Name != "":
+		return ref.Name
+	case pr.GetLabels()[pipeline.PipelineLabelKey] != "":
+		return pr.GetLabels()[pipeline.PipelineLabelKey]
+	case ref != nil && ref.Resolver != "":
+		return "resolved by " + string(ref.Resolver)
+	}
+	return "embedded pipelineSpec"
+}
+
+func paramsTable(td *model.TemplateData, prefix string, params []namedValue) g.Node {
+	if len(params) == 0 {
+		return nil
+	}
+	return Table(
+		Class("table table-zebra table-pin-rows"),
+		THead(Tr(Th(g.Text("Param")), Th(g.Text("Value")))),
+		TBody(
+			g.Map(params, func(p namedValue) g.Node {
+				return Tr(
+					Td(
+						Class("select-all"),
+						g.Text(p.Name),
+					),
+					Td(value(td, prefix+p.Name+"-", p.Value)),
+				)
+			})...,
+		),
+	)
+}
+
+func formatTime(t *metav1.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatTimeout(d *metav1.Duration) string {
+	if d == nil {
+		return "default"
+	}
+	if d.Duration == 0 {
+		return "none"
+	}
+	return d.Duration.String()
+}
@@ -0,0 +1,214 @@
+package components
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/status"
+	g "github.com/maragudk/gomponents"
+	c "github.com/maragudk/gomponents/components"
+	. "github.com/maragudk/gomponents/html"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const timelineTicks = 5
+
+// span is a bar of the timeline.
+type span struct {
+	start, end time.Time
+	// color is the daisyUI color variable the bar is painted with,
+	// if not the base color
+	color   string
+	faint   bool
+	tooltip string
+}
+
+type timelineRow struct {
+	label  string
+	indent bool
+	spans  []span
+}
+
+// PipelineTimeline renders a gantt chart of a pipelineRun, its taskRuns
+// and their steps, along with the time taskRuns spent waiting for their
+// pods to start.
+func PipelineTimeline(td *model.TemplateData) g.Node {
+	if td.PipelineRun == nil {
+		return g.Text("pipelineRun not found")
+	}
+
+	now := time.Now()
+	prStatus := td.PipelineRun.Status
+	if prStatus.StartTime == nil {
+		return RGroup(
+			PipelineRunViewTabs(td, "timeline", true),
+			g.Text("the pipelineRun has not started yet"),
+		)
+	}
+
+	origin := prStatus.StartTime.Time
+	end := timeOr(prStatus.CompletionTime, now)
+
+	rows := []timelineRow{{
+		label: td.PipelineRun.GetName(),
+		spans: []span{{
+			start:   origin,
+			end:     end,
+			color:   statusColor(status.OfPipelineRun(td.PipelineRun)),
+			tooltip: "pipelineRun: " + formatDuration(end.Sub(origin)),
+		}},
+	}}
+
+	for _, tr := range td.TaskRuns {
+		if tr == nil || tr.Status.StartTime == nil {
+			continue
+		}
+		trStart := tr.Status.StartTime.Time
+		trEnd := timeOr(tr.Status.CompletionTime, now)
+		if trEnd.After(end) {
+			end = trEnd
+		}
+
+		podStart := trEnd
+		for _, ss := range tr.Status.Steps {
+			if st := containerStartTime(ss.ContainerState); !st.IsZero() && st.Before(podStart) {
+				podStart = st
+			}
+		}
+
+		name := taskDisplayName(tr)
+		row := timelineRow{label: name}
+		if podStart.After(trStart) {
+			row.spans = append(row.spans, span{
+				start: trStart,
+				end:   podStart,
+				faint: true,
+				tooltip: fmt.Sprintf(
+					"%s queued for %s, its pod started %s after the pipelineRun",
+					name,
+					formatDuration(podStart.Sub(trStart)),
+					formatDuration(podStart.Sub(origin)),
+				),
+			})
+		}
+		row.spans = append(row.spans, span{
+			start:   podStart,
+			end:     trEnd,
+			color:   statusColor(status.OfTaskRun(&tr.Status)),
+			tooltip: name + ": " + formatDuration(trEnd.Sub(trStart)),
+		})
+		rows = append(rows, row)
+
+		stepStatuses := status.OfSteps(&tr.Status)
+		for i, ss := range tr.Status.Steps {
+			st := containerStartTime(ss.ContainerState)
+			if st.IsZero() {
+				continue
+			}
+			stEnd := now
+			if t := ss.Terminated; t != nil {
+				stEnd = t.FinishedAt.Time
+			}
+			color := statusColor(stepStatuses[i])
+			rows = append(rows, timelineRow{
+				label:  ss.Name,
+				indent: true,
+				spans: []span{{
+					start:   st,
+					end:     stEnd,
+					color:   color,
+					tooltip: name + "/" + ss.Name + ": " + formatDuration(stEnd.Sub(st)),
+				}},
+			})
+		}
+	}
+
+	total := end.Sub(origin)
+	if total <= 0 {
+		total = time.Second
+	}
+	percent := func(t time.Time) float64 {
+		p := float64(t.Sub(origin)) / float64(total) * 100
+		if p < 0 {
+			return 0
+		}
+		if p > 100 {
+			return 100
+		}
+		return p
+	}
+
+	ticks := make([]g.Node, 0, timelineTicks+1)
+	for i := 0; i <= timelineTicks; i++ {
+		offset := total * time.Duration(i) / timelineTicks
+		ticks = append(ticks, Span(
+			Class("absolute text-xs opacity-60"),
+			StyleAttr(fmt.Sprintf("left: %.2f%%; transform: translateX(-%d%%);", float64(i)*100/timelineTicks, i*100/timelineTicks)),
+			g.Text(formatDuration(offset)),
+		))
+	}
+
+	return RGroup(
+		PipelineRunViewTabs(td, "timeline", true),
+		Div(
+			Class("rounded-lg bg-base-200 p-3 text-sm"),
+			StyleAttr("display: grid; grid-template-columns: minmax(120px, max-content) 1fr; gap: 2px 12px;"),
+			Div(),
+			Div(Class("relative h-5"), g.Group(ticks)),
+			g.Group(g.Map(rows, func(r timelineRow) g.Node {
+				return g.Group([]g.Node{
+					Div(
+						c.Classes{
+							"truncate":      true,
+							"font-semibold": !r.indent,
+							"ps-4":          r.indent,
+						},
+						g.Text(r.label),
+					),
+					Div(
+						Class("relative h-5"),
+						g.Group(g.Map(r.spans, func(s span) g.Node {
+							left := percent(s.start)
+							width := percent(s.end) - left
+							background := "hsl(var(--bc) / 0.2)"
+							switch {
+							case s.faint:
+							case s.color == "":
+								background = "hsl(var(--bc) / 0.5)"
+							default:
+								background = "hsl(var(" + s.color + "))"
+							}
+							return Div(
+								Class("absolute top-1 bottom-1 rounded"),
+								StyleAttr(fmt.Sprintf(
+									"left: %.3f%%; width: max(%.3f%%, 2px); background: %s;",
+									left, width, background,
+								)),
+								TitleAttr(s.tooltip),
+							)
+						})),
+					),
+				})
+			})),
+		),
+	)
+}
+
+func containerStartTime(state corev1.ContainerState) time.Time {
+	switch {
+	case state.Running != nil:
+		return state.Running.StartedAt.Time
+	case state.Terminated != nil:
+		return state.Terminated.StartedAt.Time
+	}
+	return time.Time{}
+}
+
+func timeOr(t *metav1.Time, fallback time.Time) time.Time {
+	if t == nil {
+		return fallback
+	}
+	return t.Time
+}
@@ -0,0 +1,251 @@
+package components
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	g "github.com/maragudk/gomponents"
+	htmx "github.com/maragudk/gomponents-htmx"
+	c "github.com/maragudk/gomponents/components"
+	. "github.com/maragudk/gomponents/html"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+const (
+	// stepsView is the default pipelineRun view, showing the details
+	// of the selected taskRun step.
+	stepsView = "steps"
+
+	LogGroupByTask = "task"
+	LogGroupByTime = "time"
+)
+
+type pipelineRunView struct {
+	Name   string
+	Route  string
+	Active bool
+}
+
+func pipelineRunViews() []*pipelineRunView {
+	return []*pipelineRunView{
+		{
+			Name: "Steps",
+		},
+		{
+			Name:  "Summary",
+			Route: "pipeline-summary",
+		},
+		{
+			Name:  "Logs",
+			Route: "pipeline-log",
+		},
+		{
+			Name:  "Graph",
+			Route: "pipeline-graph",
+		},
+		{
+			Name:  "Timeline",
+			Route: "pipeline-timeline",
+		},
+		{
+			Name:  "Manifest",
+			Route: "pipeline-manifest",
+		},
+	}
+}
+
+// PipelineRunViewTabs renders the tabs switching between views of the
+// whole pipelineRun, such as its combined logs, and the step view.
+func PipelineRunViewTabs(td *model.TemplateData, active string, outOfBand bool) g.Node {
+	if td.PipelineRun == nil {
+		return nil
+	}
+
+	views := pipelineRunViews()
+
+	noneActive := true
+	for _, v := range views {
+		if strings.ToLower(v.Name) == active {
+			v.Active = true
+			noneActive = false
+		}
+	}
+	if noneActive {
+		views[0].Active = true
+	}
+
+	return Div(
+		Class("tabs tabs-boxed mb-2"),
+		ID("pipelinerun-view-tabs"),
+		g.If(
+			outOfBand,
+			htmx.SwapOOB("true"),
+		),
+		g.Group(g.Map(views, func(v *pipelineRunView) g.Node {
+			view := strings.ToLower(v.Name)
+
+			getURL := td.URLFor(v.Route, td.Namespace, td.PipelineRun.GetName())
+			pushURL := td.URLFor(
+				"list-w-pipe-view",
+				td.Namespace,
+				"pipelineruns",
+				td.PipelineRun.GetName(),
+				view,
+			)
+			if view == stepsView {
+				if td.TaskRun == nil {
+					return nil
+				}
+				getURL = td.URLFor(
+					"details-w-step",
+					td.Namespace,
+					td.TaskRun.GetName(),
+					td.Step,
+				) + "?pipelineRun=" + td.PipelineRun.GetName()
+				getURL = withState(td, getURL)
+				pushURL = withState(td, stepURL(td, td.TaskRun.GetName(), td.Step))
+			}
+
+			return A(
+				c.Classes{
+					"tab":        true,
+					"tab-active": v.Active,
+				},
+				htmx.Get(getURL),
+				htmx.Target("#taskrun-details"),
+				htmx.Swap("innerHTML"),
+				g.If(!outOfBand && v.Active && view != stepsView, htmx.Trigger("load")),
+				g.If(outOfBand || !v.Active, htmx.PushURL(pushURL)),
+				g.Text(v.Name),
+			)
+		})),
+	)
+}
+
+// taskDisplayName returns the name a taskRun is known by inside its
+// pipeline, falling back to the name of the task it references.
+func taskDisplayName(tr *pipelinev1beta1.TaskRun) string {
+	if name := tr.GetLabels()[pipeline.PipelineTaskLabelKey]; name != "" {
+		return name
+	}
+	if tr.Spec.TaskRef != nil && tr.Spec.TaskRef.Name != "" {
+		return tr.Spec.TaskRef.Name
+	}
+	return tr.GetName()
+}
+
+type prefixedLine struct {
+	model.LogLine
+	prefix string
+}
+
+// PipelineLog renders the logs of every step of a pipelineRun, either
+// interleaved by time or grouped in collapsible sections per task.
+func PipelineLog(td *model.TemplateData, groupBy string, stepLogs []model.StepLogs) g.Node {
+	logURL := td.URLFor("pipeline-log", td.Namespace, td.PipelineRun.GetName())
+
+	var content g.Node
+	if groupBy == LogGroupByTime {
+		content = interleavedLog(stepLogs)
+	} else {
+		content = groupedLog(stepLogs)
+	}
+
+	return RGroup(
+		PipelineRunViewTabs(td, "logs", true),
+		Div(
+			Class("join mb-2"),
+			g.Group(g.Map(
+				[]string{LogGroupByTask, LogGroupByTime},
+				func(by string) g.Node {
+					return Button(
+						c.Classes{
+							"btn btn-sm join-item": true,
+							"btn-active":           by == groupBy,
+						},
+						htmx.Get(logURL+"?group="+by),
+						htmx.Target("#taskrun-details"),
+						htmx.Swap("innerHTML"),
+						g.Text("by "+by),
+					)
+				},
+			)),
+		),
+		Div(
+			Class("rounded-lg bg-base-200 p-2"),
+			content,
+		),
+	)
+}
+
+func interleavedLog(stepLogs []model.StepLogs) g.Node {
+	var lines []prefixedLine
+	for _, sl := range stepLogs {
+		prefix := "[" + taskDisplayName(sl.TaskRun) + "/" + sl.Step + "] "
+		if sl.Err != nil {
+			lines = append(lines, prefixedLine{
+				model.LogLine{Content: "logs unavailable: " + sl.Err.Error()},
+				prefix,
+			})
+			continue
+		}
+		for _, l := range sl.Lines {
+			lines = append(lines, prefixedLine{l, prefix})
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Time.Before(lines[j].Time)
+	})
+
+	return Pre(
+		Class("text-sm whitespace-pre-wrap"),
+		g.Group(g.Map(lines, func(l prefixedLine) g.Node {
+			return Div(
+				Span(Class("opacity-60"), g.Text(l.prefix)),
+				g.Text(l.Content),
+			)
+		})),
+	)
+}
+
+func groupedLog(stepLogs []model.StepLogs) g.Node {
+	var groups [][]model.StepLogs
+	for i, sl := range stepLogs {
+		if i == 0 || stepLogs[i-1].TaskRun != sl.TaskRun {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], sl)
+	}
+
+	return g.Group(g.Map(groups, func(group []model.StepLogs) g.Node {
+		return Details(
+			g.Attr("open"),
+			Summary(
+				Class("font-semibold cursor-pointer"),
+				g.Text(taskDisplayName(group[0].TaskRun)),
+			),
+			g.Group(g.Map(group, func(sl model.StepLogs) g.Node {
+				return Details(
+					Class("ms-4"),
+					g.Attr("open"),
+					Summary(
+						Class("cursor-pointer"),
+						g.Text(sl.Step),
+					),
+					Pre(
+						Class("text-sm whitespace-pre-wrap"),
+						g.If(sl.Err != nil, &wrap{func() g.Node {
+							return g.Text("logs unavailable: " + sl.Err.Error())
+						}}),
+						g.Group(g.Map(sl.Lines, func(l model.LogLine) g.Node {
+							return Div(g.Text(l.Content))
+						})),
+					),
+				)
+			})),
+		)
+	}))
+}
@@ -0,0 +1,127 @@
+package components
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	g "github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+// gitURIPrefix prefixes the refSource URIs of the git resolver.
+const gitURIPrefix = "git+"
+
+// sourceFields describes where a task or pipeline was resolved from:
+// the resolver and params of its ref, and the source, digest and entry
+// point recorded in the provenance of its run.
+func sourceFields(
+	td *model.TemplateData,
+	prefix string,
+	resolver pipelinev1beta1.ResolverRef,
+	bundle string,
+	provenance *pipelinev1beta1.Provenance,
+) []field {
+	var fields []field
+	if resolver.Resolver != "" {
+		fields = append(fields, field{"Resolver", Span(
+			Class("badge badge-ghost"),
+			g.Text(string(resolver.Resolver)),
+		)})
+		for _, p := range resolver.Params {
+			fields = append(fields, field{
+				"Resolver param " + p.Name,
+				value(td, prefix+"resolver-"+p.Name+"-", p.Value),
+			})
+		}
+	}
+	if bundle != "" {
+		fields = append(fields, field{"Bundle", Span(Class("select-all break-all"), g.Text(bundle))})
+	}
+
+	if provenance == nil || provenance.RefSource == nil {
+		return fields
+	}
+	src := provenance.RefSource
+	git := gitSource(src)
+
+	if src.URI != "" {
+		var uri g.Node = Span(Class("select-all break-all"), g.Text(src.URI))
+		if safeURL(git.Repo) {
+			uri = A(Class("link link-info break-all"), Href(git.Repo), Target("_blank"), g.Text(src.URI))
+		}
+		fields = append(fields, field{"Source", uri})
+	}
+	if len(src.Digest) > 0 {
+		algorithms := make([]string, 0, len(src.Digest))
+		for alg := range src.Digest {
+			algorithms = append(algorithms, alg)
+		}
+		sort.Strings(algorithms)
+		digests := make([]g.Node, 0, len(algorithms))
+		for _, alg := range algorithms {
+			digests = append(digests, Div(
+				Class("font-mono select-all break-all"),
+				g.Text(alg+":"+src.Digest[alg]),
+			))
+		}
+		fields = append(fields, field{"Digest", Div(digests...)})
+	}
+	if src.EntryPoint != "" {
+		var entry g.Node = Span(Class("select-all"), g.Text(src.EntryPoint))
+		if u := gitWebURL(td, git); safeURL(git.Repo) && safeURL(u) {
+			entry = A(Class("link link-info"), Href(u), Target("_blank"), g.Text(src.EntryPoint))
+		}
+		fields = append(fields, field{"Entry point", entry})
+	}
+	return fields
+}
+
+// safeURL tells whether u may be linked to: resolver provided URLs
+// could otherwise run scripts through javascript: and alike schemes.
+func safeURL(u string) bool {
+	return strings.HasPrefix(u, "https://")
+}
+
+// gitSource extracts the repository, revision and path of a source
+// resolved by the git resolver. Repo is empty for other sources.
+func gitSource(src *pipelinev1beta1.RefSource) model.GitSource {
+	if !strings.HasPrefix(src.URI, gitURIPrefix) {
+		return model.GitSource{}
+	}
+	return model.GitSource{
+		Repo:     strings.TrimSuffix(strings.TrimPrefix(src.URI, gitURIPrefix), ".git"),
+		Revision: src.Digest["sha1"],
+		Path:     src.EntryPoint,
+	}
+}
+
+// gitWebURL executes the git web URL template against src, returning
+// an empty string when no template is configured or it fails.
+func gitWebURL(td *model.TemplateData, src model.GitSource) string {
+	if td.GitWebURL == nil {
+		return ""
+	}
+	var sb strings.Builder
+	if err := td.GitWebURL.Execute(&sb, src); err != nil {
+		return ""
+	}
+	return sb.String()
+}
+
+// sourceTable renders the source fields of a taskRun, if any.
+func sourceTable(fields []field) g.Node {
+	if len(fields) == 0 {
+		return nil
+	}
+	return Table(
+		Class("table table-sm"),
+		TBody(g.Map(fields, func(f field) g.Node {
+			return Tr(
+				Th(Class("w-48"), g.Text(f.name)),
+				Td(f.value),
+			)
+		})...),
+	)
+}
@@ -0,0 +1,288 @@
+package components
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/syntax"
+	g "github.com/maragudk/gomponents"
+	htmx "github.com/maragudk/gomponents-htmx"
+	c "github.com/maragudk/gomponents/components"
+	. "github.com/maragudk/gomponents/html"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+// SubstitutionPattern matches the variables tekton substitutes in step
+// scripts, such as $(params.name), $(params["name"]) or
+// $(workspaces.source.path).
+var SubstitutionPattern = regexp.MustCompile(
+	`\$\((?:params|results|workspaces|context)(?:\.[-\w]+|\[["'][^"']+["']\]|\[\*\])+\)`,
+)
+
+// ScriptToolbar switches between rendering the variables of a step
+// script as written and as their values, and between languages to
+// highlight it as. auto is the language it is highlighted as unless
+// overridden by the user.
+func ScriptToolbar(td *model.TemplateData, auto string) g.Node {
+	u := withState(td, td.URLFor("script", td.Namespace, td.TaskRun.GetName(), td.Step))
+	button := func(mode, label string) g.Node {
+		get := appendQuery(u, "script="+mode)
+		if td.Language != "" {
+			get = appendQuery(get, "language="+url.QueryEscape(td.Language))
+		}
+		return Button(
+			c.Classes{
+				"btn btn-sm join-item": true,
+				"btn-active":           td.Script == mode,
+			},
+			htmx.Get(get),
+			htmx.Target("#step-details-content"),
+			g.Text(label),
+		)
+	}
+	return Div(
+		Class("flex gap-4 mb-2"),
+		Div(
+			Class("join"),
+			button(model.RawScript, "Raw"),
+			button(model.ResolvedScript, "Resolved"),
+		),
+		Select(
+			Name("language"),
+			Class("select select-sm select-bordered"),
+			AutoComplete("off"),
+			htmx.Get(appendQuery(u, "script="+td.Script)),
+			htmx.Target("#step-details-content"),
+			Option(
+				Value(""),
+				g.If(td.Language == "", Selected()),
+				g.Text("Auto ("+auto+")"),
+			),
+			g.Group(g.Map(syntax.Languages(), func(lang string) g.Node {
+				return Option(
+					Value(lang),
+					g.If(strings.EqualFold(lang, td.Language), Selected()),
+					g.Text(lang),
+				)
+			})),
+		),
+	)
+}
+
+// Substitutions resolves the variables matched by SubstitutionPattern
+// from the browsed taskRun. Hovering a variable shows its value, or the
+// variable itself when rendering resolved scripts, which show values in
+// full as they ran.
+func Substitutions(td *model.TemplateData) func(string) syntax.Reference {
+	return func(variable string) syntax.Reference {
+		val, source, href, ok := substitute(td, variablePath(variable))
+		if !ok {
+			return syntax.Reference{Text: variable, Title: source}
+		}
+		if td.Script == model.ResolvedScript {
+			return syntax.Reference{
+				Text:  val,
+				Title: variable + "\n" + source,
+				Href:  href,
+			}
+		}
+		return syntax.Reference{
+			Text:  variable,
+			Title: truncate(val) + "\n" + source,
+			Href:  href,
+		}
+	}
+}
+
+// variablePath splits $(params["a.b"].key) into [params a.b key].
+func variablePath(variable string) []string {
+	inner := strings.TrimSuffix(strings.TrimPrefix(variable, "$("), ")")
+	var path []string
+	for inner != "" {
+		switch {
+		case inner[0] == '.':
+			inner = inner[1:]
+		case inner[0] == '[':
+			end := strings.IndexByte(inner, ']')
+			if end < 0 {
+				return append(path, inner)
+			}
+			path = append(path, strings.Trim(inner[1:end], `"'`))
+			inner = inner[end+1:]
+		default:
+			end := strings.IndexAny(inner, ".[")
+			if end < 0 {
+				end = len(inner)
+			}
+			path = append(path, inner[:end])
+			inner = inner[end:]
+		}
+	}
+	return path
+}
+
+// substitute returns the value tekton substitutes for the variable at
+// path, along with where it comes from and, for params, a link to their
+// row. When the value cannot be told, source explains why.
+func substitute(td *model.TemplateData, path []string) (val, source, href string, ok bool) {
+	if len(path) < 2 {
+		return "", "unknown variable", "", false
+	}
+	tr := td.TaskRun
+	spec := td.TaskSpecOf(tr)
+	attr := ""
+	if len(path) > 2 {
+		attr = path[2]
+	}
+
+	switch path[0] {
+	case "params":
+		name := path[1]
+		href = "#" + paramRowID(name)
+		for _, p := range tr.Spec.Params {
+			if p.Name == name {
+				return paramString(p.Value, attr), "param " + name, href, true
+			}
+		}
+		if spec != nil {
+			for _, ps := range spec.Params {
+				if ps.Name == name && ps.Default != nil {
+					return paramString(*ps.Default, attr), "default of param " + name, href, true
+				}
+			}
+		}
+		return "", "param " + name + " was not provided", href, false
+
+	case "results":
+		name := path[1]
+		val = pipeline.DefaultResultPath + "/" + name
+		source = "path of result " + name
+		for _, r := range td.StatusOf(tr).TaskRunResults {
+			if r.Name == name {
+				source += ", written " + truncate(paramString(r.Value, ""))
+			}
+		}
+		return val, source, "", true
+
+	case "workspaces":
+		name := path[1]
+		source = "workspace " + name
+		var decl *pipelinev1beta1.WorkspaceDeclaration
+		if spec != nil {
+			for i := range spec.Workspaces {
+				if spec.Workspaces[i].Name == name {
+					decl = &spec.Workspaces[i]
+				}
+			}
+		}
+		var binding *pipelinev1beta1.WorkspaceBinding
+		for i := range tr.Spec.Workspaces {
+			if tr.Spec.Workspaces[i].Name == name {
+				binding = &tr.Spec.Workspaces[i]
+			}
+		}
+		switch attr {
+		case "path":
+			if decl == nil {
+				return "", source + " is not declared", "", false
+			}
+			return decl.GetMountPath(), source, "", true
+		case "bound":
+			return strconv.FormatBool(binding != nil), source, "", true
+		case "claim":
+			if binding == nil || binding.PersistentVolumeClaim == nil {
+				return "", source, "", true
+			}
+			return binding.PersistentVolumeClaim.ClaimName, source, "", true
+		}
+		return "", source + " volume names are generated", "", false
+
+	case "context":
+		return contextValue(td, path[1:])
+	}
+	return "", "unknown variable", "", false
+}
+
+// contextValue returns the value of $(context.*) variables.
+func contextValue(td *model.TemplateData, path []string) (val, source, href string, ok bool) {
+	tr := td.TaskRun
+	pr := td.PipelineRun
+	if pr == nil {
+		pr = td.ParentPipelineRun
+	}
+	variable := strings.Join(path, ".")
+	source = "context"
+	switch variable {
+	case "taskRun.name":
+		return tr.GetName(), source, "", true
+	case "taskRun.namespace":
+		return tr.GetNamespace(), source, "", true
+	case "taskRun.uid":
+		return string(tr.GetUID()), source, "", true
+	case "task.name":
+		labels := tr.GetLabels()
+		for _, name := range []string{
+			labels[pipeline.TaskLabelKey],
+			labels[pipeline.ClusterTaskLabelKey],
+		} {
+			if name != "" {
+				return name, source, "", true
+			}
+		}
+		if ref := tr.Spec.TaskRef; ref != nil && ref.Name != "" {
+			return ref.Name, source, "", true
+		}
+		return tr.GetName(), source, "", true
+	case "task.retry-count":
+		retries := len(tr.Status.RetriesStatus)
+		if td.Attempt > 0 && td.Attempt <= retries {
+			retries = td.Attempt - 1
+		}
+		return strconv.Itoa(retries), source, "", true
+	case "pipelineRun.name":
+		if pr != nil {
+			return pr.GetName(), source, "", true
+		}
+		if name := tr.GetLabels()[pipeline.PipelineRunLabelKey]; name != "" {
+			return name, source, "", true
+		}
+	case "pipelineRun.namespace":
+		return tr.GetNamespace(), source, "", true
+	case "pipelineRun.uid":
+		if pr != nil {
+			return string(pr.GetUID()), source, "", true
+		}
+	case "pipeline.name":
+		if name := tr.GetLabels()[pipeline.PipelineLabelKey]; name != "" {
+			return name, source, "", true
+		}
+	}
+	return "", "context." + variable + " is unknown", "", false
+}
+
+// paramString formats v the way tekton substitutes it in scripts.
+// key selects an object key, or all items of arrays when "*".
+func paramString(v pipelinev1beta1.ParamValue, key string) string {
+	switch v.Type {
+	case pipelinev1beta1.ParamTypeArray:
+		return strings.Join(v.ArrayVal, " ")
+	case pipelinev1beta1.ParamTypeObject:
+		if key != "" && key != "*" {
+			return v.ObjectVal[key]
+		}
+		js, _ := json.Marshal(v.ObjectVal)
+		return string(js)
+	}
+	return v.StringVal
+}
+
+// paramRowID is the id of the row of a param in the params table of the
+// browsed taskRun.
+func paramRowID(name string) string {
+	return "tr-param-row-" + name
+}
@@ -0,0 +1,39 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/syntax"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+func TestResolvedScriptKeepsFullValues(t *testing.T) {
+	message := "first line\nsecond line\n" + strings.Repeat("x", 2*maxInlineValue)
+	tr := &pipelinev1beta1.TaskRun{
+		Spec: pipelinev1beta1.TaskRunSpec{
+			Params: pipelinev1beta1.Params{{
+				Name:  "message",
+				Value: *pipelinev1beta1.NewStructuredValues(message),
+			}},
+		},
+	}
+	td := &model.TemplateData{TaskRun: tr, Script: model.ResolvedScript}
+
+	var sb strings.Builder
+	err := syntax.FormatHTML(
+		&sb,
+		"#!/bin/sh\necho \"$(params.message)\"\n",
+		syntax.WithLanguage("bash"),
+		syntax.WithReferences(SubstitutionPattern, Substitutions(td)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(message, "\n") {
+		if !strings.Contains(sb.String(), line) {
+			t.Errorf("resolved script is missing %q:\n%s", line, sb.String())
+		}
+	}
+}
@@ -27,12 +27,17 @@ func Shell(content ...model.TektonComponent) model.TektonComponent {
 				Script(
 					Src("/_static/tailwindcss.js"),
 				),
+				Link(
+					Href(td.URLFor("syntax-css", td.SyntaxStyle)),
+					Rel("stylesheet"),
+					Type("text/css"),
+				),
 			},
 			Body: append(
 				g.Map(content, func(tc model.TektonComponent) g.Node {
 					return tc(td)
 				}),
-				DataAttr("theme", "night"),
+				DataAttr("theme", td.Theme),
 			),
 		})
 	}
@@ -0,0 +1,72 @@
+package components
+
+import (
+	"github.com/cezarguimaraes/tkn-dash/internal/status"
+	g "github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+)
+
+// statusIcons holds the bootstrap icon rendered for each status.
+var statusIcons = map[status.Status]string{
+	status.Succeeded: `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" fill="currentColor" class="text-success bi bi-check-circle-fill" viewBox="0 0 16 16">
+                <path d="M16 8A8 8 0 1 1 0 8a8 8 0 0 1 16 0zm-3.97-3.03a.75.75 0 0 0-1.08.022L7.477 9.417 5.384 7.323a.75.75 0 0 0-1.06 1.06L6.97 11.03a.75.75 0 0 0 1.079-.02l3.992-4.99a.75.75 0 0 0-.01-1.05z"/>
+            </svg>`,
+	status.Failed: `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" fill="currentColor" class="text-error bi bi-x-circle-fill" viewBox="0 0 16 16">
+                <path d="M16 8A8 8 0 1 1 0 8a8 8 0 0 1 16 0zM5.354 4.646a.5.5 0 1 0-.708.708L7.293 8l-2.647 2.646a.5.5 0 0 0 .708.708L8 8.707l2.646 2.647a.5.5 0 0 0 .708-.708L8.707 8l2.647-2.646a.5.5 0 0 0-.708-.708L8 7.293 5.354 4.646z"/>
+            </svg>`,
+	status.Running: `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" fill="currentColor" class="text-info animate-pulse bi bi-circle-fill" viewBox="0 0 16 16">
+                <circle cx="8" cy="8" r="8"/>
+            </svg>`,
+	status.Pending: `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" fill="currentColor" class="opacity-60 bi bi-clock" viewBox="0 0 16 16">
+                <path d="M8 3.5a.5.5 0 0 0-1 0V9a.5.5 0 0 0 .252.434l3.5 2a.5.5 0 0 0 .496-.868L8 8.71V3.5z"/>
+                <path d="M8 16A8 8 0 1 0 8 0a8 8 0 0 0 0 16zm7-8A7 7 0 1 1 1 8a7 7 0 0 1 14 0z"/>
+            </svg>`,
+	status.Skipped: `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" fill="currentColor" class="opacity-60 bi bi-dash-circle" viewBox="0 0 16 16">
+                <path d="M8 15A7 7 0 1 1 8 1a7 7 0 0 1 0 14zm0 1A8 8 0 1 0 8 0a8 8 0 0 0 0 16z"/>
+                <path d="M4 8a.5.5 0 0 1 .5-.5h7a.5.5 0 0 1 0 1h-7A.5.5 0 0 1 4 8z"/>
+            </svg>`,
+	status.Cancelled: `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" fill="currentColor" class="opacity-70 bi bi-slash-circle-fill" viewBox="0 0 16 16">
+                <path d="M16 8A8 8 0 1 1 0 8a8 8 0 0 1 16 0zm-4.646-2.646a.5.5 0 0 0-.708-.708l-6 6a.5.5 0 0 0 .708.708l6-6z"/>
+            </svg>`,
+	status.TimedOut: `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" fill="currentColor" class="text-error bi bi-clock-fill" viewBox="0 0 16 16">
+                <path d="M16 8A8 8 0 1 1 0 8a8 8 0 0 1 16 0zM8 3.5a.5.5 0 0 0-1 0V9a.5.5 0 0 0 .252.434l3.5 2a.5.5 0 0 0 .496-.868L8 8.71V3.5z"/>
+            </svg>`,
+	status.ContinuedOnError: `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" fill="currentColor" class="text-warning bi bi-exclamation-circle-fill" viewBox="0 0 16 16">
+                <path d="M16 8A8 8 0 1 1 0 8a8 8 0 0 1 16 0zM8 4a.905.905 0 0 0-.9.995l.35 3.507a.552.552 0 0 0 1.1 0l.35-3.507A.905.905 0 0 0 8 4zm.002 6a1 1 0 1 0 0 2 1 1 0 0 0 0-2z"/>
+            </svg>`,
+}
+
+// statusIcon renders the icon of s, with a tooltip naming it and the
+// reason behind it, if any.
+func statusIcon(s status.Status, reason string) g.Node {
+	icon, ok := statusIcons[s]
+	if !ok {
+		return g.Text("")
+	}
+	tooltip := s.Label()
+	if reason != "" && reason != string(s) {
+		tooltip += ": " + reason
+	}
+	return Span(
+		Class("inline-flex"),
+		TitleAttr(tooltip),
+		Aria("label", tooltip),
+		g.Raw(icon),
+	)
+}
+
+// statusColor returns the daisyUI color variable s is painted with, or
+// an empty string for statuses painted with the base color.
+func statusColor(s status.Status) string {
+	switch s {
+	case status.Succeeded:
+		return "--su"
+	case status.Failed, status.TimedOut:
+		return "--er"
+	case status.Running:
+		return "--in"
+	case status.ContinuedOnError:
+		return "--wa"
+	}
+	return ""
+}
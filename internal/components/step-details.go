@@ -0,0 +1,321 @@
+package components
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	g "github.com/maragudk/gomponents"
+	c "github.com/maragudk/gomponents/components"
+	. "github.com/maragudk/gomponents/html"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// stepDetailsView is the id of the step details tab. Its route is
+// named differently, as "details" is the route of the whole panel.
+const stepDetailsView = "details"
+
+// StepDetails renders what is known about the container of Step: its
+// spec, the image it ran and how it terminated.
+func StepDetails(td *model.TemplateData) g.Node {
+	if td.TaskRun == nil {
+		return g.Text("taskRun not found")
+	}
+	spec, imageID, onError := containerSpec(td)
+	if spec == nil {
+		return RGroup(
+			StepDetailsTabs(td, stepDetailsView, true),
+			g.Text("container not found"),
+		)
+	}
+
+	fields := []field{
+		{"Image", Span(Class("select-all"), g.Text(spec.Image))},
+	}
+	if imageID != "" {
+		fields = append(fields, field{"Image ID", Span(Class("select-all break-all"), g.Text(imageID))})
+	}
+	if len(spec.Command) > 0 {
+		fields = append(fields, field{"Command", commandLine(spec.Command)})
+	}
+	if len(spec.Args) > 0 {
+		fields = append(fields, field{"Args", commandLine(spec.Args)})
+	}
+	if spec.WorkingDir != "" {
+		fields = append(fields, field{"Working dir", Span(Class("select-all"), g.Text(spec.WorkingDir))})
+	}
+	if onError != "" {
+		fields = append(fields, field{"On error", g.Text(onError)})
+	}
+
+	if state := containerState(td); state != nil {
+		fields = append(fields, stateFields(*state)...)
+	}
+
+	return RGroup(
+		StepDetailsTabs(td, stepDetailsView, true),
+		Div(
+			Class("flex flex-col gap-3 p-2 bg-base-200"),
+			Table(
+				Class("table table-sm"),
+				TBody(g.Map(fields, func(f field) g.Node {
+					return Tr(
+						Th(Class("w-48"), g.Text(f.name)),
+						Td(f.value),
+					)
+				})...),
+			),
+			envTable(spec.Env, spec.EnvFrom),
+			volumeMountsTable(spec.VolumeMounts),
+			resourcesTable(spec.Resources),
+		),
+	)
+}
+
+// containerSpec returns the spec of the container running Step, along
+// with the digest of the image it ran and its onError behavior.
+func containerSpec(td *model.TemplateData) (*corev1.Container, string, string) {
+	status := td.StatusOf(td.TaskRun)
+	spec := td.TaskSpecOf(td.TaskRun)
+	switch td.ContainerKind {
+	case model.SidecarContainer:
+		var imageID string
+		for _, sc := range status.Sidecars {
+			if sc.Name == td.Step {
+				imageID = sc.ImageID
+			}
+		}
+		if spec == nil {
+			return nil, "", ""
+		}
+		for i := range spec.Sidecars {
+			if spec.Sidecars[i].Name == td.Step {
+				return spec.Sidecars[i].ToK8sContainer(), imageID, ""
+			}
+		}
+	case model.InitContainer:
+		if td.Pod == nil {
+			return nil, "", ""
+		}
+		var imageID string
+		for _, cs := range td.Pod.Status.InitContainerStatuses {
+			if cs.Name == td.Step {
+				imageID = cs.ImageID
+			}
+		}
+		for i := range td.Pod.Spec.InitContainers {
+			if td.Pod.Spec.InitContainers[i].Name == td.Step {
+				return &td.Pod.Spec.InitContainers[i], imageID, ""
+			}
+		}
+	default:
+		var imageID string
+		for _, ss := range status.Steps {
+			if ss.Name == td.Step {
+				imageID = ss.ImageID
+			}
+		}
+		if spec == nil {
+			return nil, "", ""
+		}
+		for i := range spec.Steps {
+			if spec.Steps[i].Name == td.Step {
+				return spec.Steps[i].ToK8sContainer(), imageID, string(spec.Steps[i].OnError)
+			}
+		}
+	}
+	return nil, "", ""
+}
+
+func stateFields(state corev1.ContainerState) []field {
+	var fields []field
+	switch {
+	case state.Waiting != nil:
+		fields = append(fields, field{"State", g.Text("waiting")})
+		if r := state.Waiting.Reason; r != "" {
+			fields = append(fields, field{"Reason", g.Text(r)})
+		}
+		if m := state.Waiting.Message; m != "" {
+			fields = append(fields, field{"Message", terminationMessage(m)})
+		}
+	case state.Running != nil:
+		fields = append(fields,
+			field{"State", g.Text("running")},
+			field{"Started", g.Text(formatTime(&state.Running.StartedAt))},
+			field{"Duration", g.Text(formatDuration(containerDuration(state)))},
+		)
+	case state.Terminated != nil:
+		t := state.Terminated
+		fields = append(fields,
+			field{"State", g.Text("terminated")},
+			field{"Exit code", Span(
+				c.Classes{
+					"font-mono":  true,
+					"text-error": t.ExitCode != 0,
+				},
+				g.Textf("%d", t.ExitCode),
+			)},
+		)
+		if t.Reason != "" {
+			fields = append(fields, field{"Reason", g.Text(t.Reason)})
+		}
+		if t.Message != "" {
+			fields = append(fields, field{"Message", terminationMessage(t.Message)})
+		}
+		fields = append(fields,
+			field{"Started", g.Text(formatTime(&t.StartedAt))},
+			field{"Finished", g.Text(formatTime(&t.FinishedAt))},
+			field{"Duration", g.Text(formatDuration(containerDuration(state)))},
+		)
+	}
+	return fields
+}
+
+// terminationMessage renders a container message. Tekton steps report
+// their results as JSON in it, which is collapsed like result values.
+func terminationMessage(m string) g.Node {
+	if len(m) > maxInlineValue || strings.Contains(m, "\n") {
+		return Details(
+			Summary(Class("cursor-pointer font-mono"), g.Text(truncate(m))),
+			Pre(Class("text-sm whitespace-pre-wrap select-all"), g.Text(m)),
+		)
+	}
+	return Span(Class("select-all"), g.Text(m))
+}
+
+func commandLine(args []string) g.Node {
+	return Code(
+		Class("select-all whitespace-pre-wrap"),
+		g.Text(strings.Join(args, " ")),
+	)
+}
+
+// envTable lists the environment of a container. Values coming from
+// secrets and other sources are shown as references only.
+func envTable(env []corev1.EnvVar, envFrom []corev1.EnvFromSource) g.Node {
+	if len(env) == 0 && len(envFrom) == 0 {
+		return nil
+	}
+
+	rows := make([]g.Node, 0, len(env)+len(envFrom))
+	for _, e := range env {
+		var v g.Node = Span(Class("select-all"), g.Text(e.Value))
+		if e.ValueFrom != nil {
+			kind, ref := envSource(e.ValueFrom)
+			v = reference(kind, ref)
+		}
+		rows = append(rows, Tr(
+			Td(Class("select-all font-mono"), g.Text(e.Name)),
+			Td(v),
+		))
+	}
+	for _, ef := range envFrom {
+		name := ef.Prefix + "*"
+		switch {
+		case ef.SecretRef != nil:
+			rows = append(rows, Tr(
+				Td(Class("font-mono"), g.Text(name)),
+				Td(reference("secret", ef.SecretRef.Name)),
+			))
+		case ef.ConfigMapRef != nil:
+			rows = append(rows, Tr(
+				Td(Class("font-mono"), g.Text(name)),
+				Td(reference("configMap", ef.ConfigMapRef.Name)),
+			))
+		}
+	}
+
+	return Table(
+		Class("table table-zebra table-sm"),
+		THead(Tr(Th(g.Text("Env")), Th(g.Text("Value")))),
+		TBody(rows...),
+	)
+}
+
+func envSource(src *corev1.EnvVarSource) (kind, ref string) {
+	switch {
+	case src.SecretKeyRef != nil:
+		return "secret", src.SecretKeyRef.Name + "/" + src.SecretKeyRef.Key
+	case src.ConfigMapKeyRef != nil:
+		return "configMap", src.ConfigMapKeyRef.Name + "/" + src.ConfigMapKeyRef.Key
+	case src.FieldRef != nil:
+		return "field", src.FieldRef.FieldPath
+	case src.ResourceFieldRef != nil:
+		return "resource", src.ResourceFieldRef.Resource
+	}
+	return "unknown", ""
+}
+
+func reference(kind, ref string) g.Node {
+	return Span(
+		Span(Class("badge badge-ghost me-2"), g.Text(kind)),
+		Span(Class("select-all font-mono"), g.Text(ref)),
+	)
+}
+
+func volumeMountsTable(mounts []corev1.VolumeMount) g.Node {
+	if len(mounts) == 0 {
+		return nil
+	}
+	return Table(
+		Class("table table-zebra table-sm"),
+		THead(Tr(
+			Th(g.Text("Volume")),
+			Th(g.Text("Mount path")),
+			Th(g.Text("Sub path")),
+			Th(g.Text("Mode")),
+		)),
+		TBody(g.Map(mounts, func(m corev1.VolumeMount) g.Node {
+			mode := "rw"
+			if m.ReadOnly {
+				mode = "ro"
+			}
+			return Tr(
+				Td(Class("select-all"), g.Text(m.Name)),
+				Td(Class("select-all font-mono"), g.Text(m.MountPath)),
+				Td(Class("select-all font-mono"), g.Text(m.SubPath)),
+				Td(g.Text(mode)),
+			)
+		})...),
+	)
+}
+
+func resourcesTable(res corev1.ResourceRequirements) g.Node {
+	names := map[corev1.ResourceName]bool{}
+	for n := range res.Requests {
+		names[n] = true
+	}
+	for n := range res.Limits {
+		names[n] = true
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sorted := make([]string, 0, len(names))
+	for n := range names {
+		sorted = append(sorted, string(n))
+	}
+	sort.Strings(sorted)
+
+	quantity := func(l corev1.ResourceList, n string) string {
+		if q, ok := l[corev1.ResourceName(n)]; ok {
+			return q.String()
+		}
+		return "-"
+	}
+	return Table(
+		Class("table table-zebra table-sm"),
+		THead(Tr(
+			Th(g.Text("Resource")),
+			Th(g.Text("Request")),
+			Th(g.Text("Limit")),
+		)),
+		TBody(g.Map(sorted, func(n string) g.Node {
+			return Tr(
+				Td(g.Text(n)),
+				Td(g.Text(quantity(res.Requests, n))),
+				Td(g.Text(quantity(res.Limits, n))),
+			)
+		})...),
+	)
+}
@@ -0,0 +1,137 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	g "github.com/maragudk/gomponents"
+	htmx "github.com/maragudk/gomponents-htmx"
+	c "github.com/maragudk/gomponents/components"
+	. "github.com/maragudk/gomponents/html"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	LogTimestampsPref = "timestamps"
+	LogRelativePref   = "relative"
+	LogWrapPref       = "wrap"
+)
+
+// StepLog renders the log viewer of a single step, along with the
+// toggles for the user's log preferences.
+func StepLog(
+	td *model.TemplateData,
+	prefs model.LogPreferences,
+	lines []model.LogLine,
+	err error,
+) g.Node {
+	start := stepStartTime(td)
+
+	return RGroup(
+		StepDetailsTabs(td, "log", true),
+		Div(
+			Class("flex gap-4 mb-2"),
+			logToggle(td, LogTimestampsPref, "Timestamps", prefs.Timestamps),
+			logToggle(td, LogRelativePref, "Offset from step start", prefs.Relative),
+			logToggle(td, LogWrapPref, "Wrap lines", prefs.Wrap),
+		),
+		Pre(
+			c.Classes{
+				"text-sm bg-base-200 rounded-lg p-2": true,
+				"whitespace-pre-wrap break-all":      prefs.Wrap,
+				"whitespace-pre overflow-x-auto":     !prefs.Wrap,
+			},
+			g.If(err != nil, &wrap{func() g.Node {
+				return g.Text("logs unavailable: " + err.Error())
+			}}),
+			g.Group(g.Map(lines, func(l model.LogLine) g.Node {
+				return Div(
+					g.If(prefs.Timestamps && !l.Time.IsZero(), Span(
+						Class("opacity-60 me-2"),
+						g.Text(l.Time.UTC().Format(time.RFC3339)),
+					)),
+					g.If(prefs.Relative && !l.Time.IsZero() && !start.IsZero(), Span(
+						Class("opacity-60 me-2"),
+						g.Text(formatOffset(l.Time.Sub(start))),
+					)),
+					g.Text(l.Content),
+				)
+			})),
+		),
+	)
+}
+
+func logToggle(td *model.TemplateData, pref, label string, value bool) g.Node {
+	return Label(
+		Class("label cursor-pointer gap-2"),
+		Input(
+			Type("checkbox"),
+			Class("toggle toggle-sm"),
+			g.If(value, g.Attr("checked")),
+			htmx.Get(appendQuery(
+				withState(td, td.URLFor("log", td.Namespace, td.TaskRun.GetName(), td.Step)),
+				pref+"="+strconv.FormatBool(!value),
+			)),
+			htmx.Target("#step-details-content"),
+		),
+		Span(Class("label-text"), g.Text(label)),
+	)
+}
+
+// stepStartTime returns when the container of the given step started,
+// or the zero time if it did not start yet.
+func stepStartTime(td *model.TemplateData) time.Time {
+	state := containerState(td)
+	if state == nil {
+		return time.Time{}
+	}
+	return containerStartTime(*state)
+}
+
+// containerState returns the state of the container running Step.
+func containerState(td *model.TemplateData) *corev1.ContainerState {
+	status := td.StatusOf(td.TaskRun)
+	switch td.ContainerKind {
+	case model.SidecarContainer:
+		for _, sc := range status.Sidecars {
+			if sc.Name == td.Step {
+				return &sc.ContainerState
+			}
+		}
+	case model.InitContainer:
+		if td.Pod == nil {
+			return nil
+		}
+		for _, cs := range td.Pod.Status.InitContainerStatuses {
+			if cs.Name == td.Step {
+				return &cs.State
+			}
+		}
+	default:
+		for _, ss := range status.Steps {
+			if ss.Name == td.Step {
+				return &ss.ContainerState
+			}
+		}
+	}
+	return nil
+}
+
+// formatOffset formats d as +[h:]mm:ss.mmm
+func formatOffset(d time.Duration) string {
+	sign := "+"
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+	ms := (d % time.Second) / time.Millisecond
+	if h > 0 {
+		return fmt.Sprintf("%s%d:%02d:%02d.%03d", sign, h, m, s, ms)
+	}
+	return fmt.Sprintf("%s%02d:%02d.%03d", sign, m, s, ms)
+}
@@ -2,13 +2,19 @@ package components
 
 import (
 	"io"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/status"
 	g "github.com/maragudk/gomponents"
 	htmx "github.com/maragudk/gomponents-htmx"
 	c "github.com/maragudk/gomponents/components"
 	. "github.com/maragudk/gomponents/html"
 	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
 )
 
 type renders[T any] func(T) g.Node
@@ -16,8 +22,27 @@ type renders[T any] func(T) g.Node
 func taskRun(td *model.TemplateData, outOfBand bool) renders[*pipelinev1beta1.TaskRun] {
 	return func(tr *pipelinev1beta1.TaskRun) g.Node {
 		if tr == nil {
-			return g.Text("this should not happen")
+			return nil
 		}
+		st := td.StatusOf(tr)
+
+		var sidecars, inits []g.Node
+		for _, sc := range st.Sidecars {
+			sidecars = append(sidecars, container(
+				td, tr, model.SidecarContainer, sc.Name, sc.ContainerState,
+				status.OfContainer(sc.ContainerState, ""),
+			))
+		}
+		// the pod is only resolved for the taskRun being browsed
+		if td.Pod != nil && td.TaskRun.GetName() == tr.GetName() {
+			for _, cs := range td.Pod.Status.InitContainerStatuses {
+				inits = append(inits, container(
+					td, tr, model.InitContainer, cs.Name, cs.State,
+					status.OfContainer(cs.State, ""),
+				))
+			}
+		}
+
 		return Li(
 			ID(tr.GetName()),
 			g.If(outOfBand, htmx.SwapOOB("true")),
@@ -25,57 +50,212 @@ func taskRun(td *model.TemplateData, outOfBand bool) renders[*pipelinev1beta1.Ta
 				g.Attr("open"),
 				Summary(
 					Class("font-semibold"),
-					g.Text(tr.Spec.TaskRef.Name),
+					statusIcon(status.OfTaskRun(st), conditionReason(st.GetCondition(apis.ConditionSucceeded))),
+					taskRunLabel(td, tr),
 				),
 				Ul(
-					g.Group(g.Map(tr.Status.Steps, step(td, tr))),
+					g.If(len(st.Steps) == 0, waitingEntry(td, tr, st)),
+					g.Group(g.Map(st.Steps, step(td, tr, st))),
+					g.If(len(sidecars) > 0, g.Group(append(
+						[]g.Node{Li(Class("menu-title"), g.Text("Sidecars"))},
+						sidecars...,
+					))),
+					g.If(len(inits) > 0, g.Group(append(
+						[]g.Node{Li(Class("menu-title"), g.Text("Init containers"))},
+						inits...,
+					))),
 				),
 			),
 		)
 	}
 }
 
+// taskRunLabel names a taskRun in the tasks panel. Taskruns fanned out
+// by a matrix are told apart by their matrix params.
+func taskRunLabel(td *model.TemplateData, tr *pipelinev1beta1.TaskRun) g.Node {
+	name := g.Text(taskDisplayName(tr))
+	if combination := matrixCombination(td, tr); combination != "" {
+		return g.Group([]g.Node{
+			name,
+			Span(Class("font-mono font-normal"), g.Text(combination)),
+		})
+	}
+	return name
+}
+
+// waitingEntry renders a menu entry for taskRuns without steps,
+// linking to the reason they have none.
+func waitingEntry(
+	td *model.TemplateData,
+	tr *pipelinev1beta1.TaskRun,
+	st *pipelinev1beta1.TaskRunStatus,
+) g.Node {
+	s := status.OfTaskRun(st)
+	reason := conditionReason(st.GetCondition(apis.ConditionSucceeded))
+	detailsURL := td.URLFor("details-wo-step", tr.GetNamespace(), tr.GetName())
+	if td.PipelineRun != nil {
+		detailsURL = detailsURL + "?pipelineRun=" + td.PipelineRun.GetName()
+	}
+	return Li(
+		A(
+			c.Classes{
+				"active": td.TaskRun.GetName() == tr.GetName() && td.Step == "",
+				"my-1":   true,
+			},
+			htmx.Get(detailsURL),
+			htmx.Target("#taskrun-details"),
+			htmx.PushURL(taskRunURL(td, tr.GetName())),
+			htmx.Swap("innerHTML"),
+			statusIcon(s, reason),
+			g.Text(s.Label()),
+			g.If(reason != "" && reason != string(s), Span(
+				Class("text-xs opacity-60"),
+				g.Text(reason),
+			)),
+		),
+	)
+}
+
 func step(
 	td *model.TemplateData,
 	tr *pipelinev1beta1.TaskRun,
+	st *pipelinev1beta1.TaskRunStatus,
 ) renders[pipelinev1beta1.StepState] {
+	statuses := map[string]status.Status{}
+	for i, s := range status.OfSteps(st) {
+		statuses[st.Steps[i].Name] = s
+	}
 	return func(ss pipelinev1beta1.StepState) g.Node {
-		success := ss.Terminated != nil &&
-			ss.Terminated.ExitCode == 0
-		active := td.TaskRun.GetName() == tr.GetName() &&
-			td.Step == ss.Name
-		var indicator g.Node
-		if success {
-			indicator = iconFor("Succeeded")
-		} else {
-			indicator = iconFor("Failed")
-		}
-		detailsURL := td.URLFor(
-			"details-w-step",
-			tr.GetNamespace(),
-			tr.GetName(),
-			ss.Name,
-		)
-		if td.PipelineRun != nil {
-			detailsURL = detailsURL + "?pipelineRun=" + td.PipelineRun.GetName()
-		}
-		return Li(
-			A(
-				c.Classes{
-					"active": active,
-					"my-1":   true,
-				},
-				htmx.Get(detailsURL),
-				htmx.Target("#taskrun-details"),
-				htmx.PushURL(stepURL(td, tr.GetName(), ss.Name)),
-				htmx.Swap("innerHTML"),
-				Span(
-					indicator,
-				),
-				g.Text(ss.Name),
+		return container(td, tr, model.StepContainer, ss.Name, ss.ContainerState, statuses[ss.Name])
+	}
+}
+
+// container renders a menu entry for one of the containers of tr,
+// linking to its details.
+func container(
+	td *model.TemplateData,
+	tr *pipelinev1beta1.TaskRun,
+	kind, name string,
+	state corev1.ContainerState,
+	s status.Status,
+) g.Node {
+	active := td.TaskRun.GetName() == tr.GetName() &&
+		td.Step == name && td.ContainerKind == kind
+	var reason string
+	switch {
+	case state.Terminated != nil:
+		reason = state.Terminated.Reason
+	case state.Waiting != nil:
+		reason = state.Waiting.Reason
+	}
+	indicator := statusIcon(s, reason)
+	detailsURL := td.URLFor(
+		"details-w-step",
+		tr.GetNamespace(),
+		tr.GetName(),
+		name,
+	)
+	if td.PipelineRun != nil {
+		detailsURL = detailsURL + "?pipelineRun=" + td.PipelineRun.GetName()
+	}
+	pushURL := stepURL(td, tr.GetName(), name)
+	if kind != model.StepContainer {
+		detailsURL = appendQuery(detailsURL, "container="+kind)
+		pushURL = appendQuery(pushURL, "container="+kind)
+	}
+	// stay on the browsed attempt while moving between its steps
+	if td.TaskRun.GetName() == tr.GetName() && td.Attempt > 0 {
+		detailsURL = appendQuery(detailsURL, "attempt="+strconv.Itoa(td.Attempt))
+		pushURL = appendQuery(pushURL, "attempt="+strconv.Itoa(td.Attempt))
+	}
+	duration := containerDuration(state)
+	return Li(
+		A(
+			c.Classes{
+				"active": active,
+				"my-1":   true,
+			},
+			htmx.Get(detailsURL),
+			htmx.Target("#taskrun-details"),
+			htmx.PushURL(pushURL),
+			htmx.Swap("innerHTML"),
+			Span(
+				indicator,
 			),
-		)
+			g.Text(name),
+			g.If(duration > 0, Span(
+				Class("text-xs opacity-60"),
+				g.Text(formatDuration(duration)),
+			)),
+		),
+	)
+}
+
+// skipCategory groups the reasons a task may be skipped for by
+// whether it was skipped by its own conditions, by its dependencies,
+// because another task failed or because the pipelineRun timed out or
+// was cancelled.
+func skipCategory(reason pipelinev1beta1.SkippingReason) (label, badge string) {
+	switch reason {
+	case pipelinev1beta1.WhenExpressionsSkip,
+		pipelinev1beta1.EmptyArrayInMatrixParams:
+		return "condition", "badge-info"
+	case pipelinev1beta1.ParentTasksSkip,
+		pipelinev1beta1.MissingResultsSkip:
+		return "dependency", "badge-warning"
+	case pipelinev1beta1.PipelineTimedOutSkip,
+		pipelinev1beta1.TasksTimedOutSkip,
+		pipelinev1beta1.FinallyTimedOutSkip:
+		return "timed out", "badge-error"
+	case pipelinev1beta1.StoppingSkip:
+		return "failure", "badge-error"
+	case pipelinev1beta1.GracefullyCancelledSkip,
+		pipelinev1beta1.GracefullyStoppedSkip:
+		return "cancelled", "badge-error"
 	}
+	return "skipped", "badge-ghost"
+}
+
+func skippedTasks(td *model.TemplateData) g.Node {
+	if td.PipelineRun == nil || len(td.PipelineRun.Status.SkippedTasks) == 0 {
+		return nil
+	}
+	return g.Group(append(
+		[]g.Node{Li(Class("menu-title"), g.Text("Skipped"))},
+		g.Map(td.PipelineRun.Status.SkippedTasks, skippedTask)...,
+	))
+}
+
+func skippedTask(st pipelinev1beta1.SkippedTask) g.Node {
+	label, badge := skipCategory(st.Reason)
+	return Li(
+		Details(
+			Summary(
+				Class("opacity-70"),
+				g.Text(st.Name),
+				Span(Class("badge badge-sm "+badge), g.Text(label)),
+			),
+			Div(
+				Class("text-sm px-4 py-1"),
+				P(g.Text(string(st.Reason))),
+				g.If(len(st.WhenExpressions) > 0, Table(
+					Class("table table-xs mt-1"),
+					THead(Tr(
+						Th(g.Text("Input")),
+						Th(g.Text("Operator")),
+						Th(g.Text("Values")),
+					)),
+					TBody(g.Map(st.WhenExpressions, func(we pipelinev1beta1.WhenExpression) g.Node {
+						return Tr(
+							Td(Class("select-all"), g.Text(we.Input)),
+							Td(g.Text(string(we.Operator))),
+							Td(Class("select-all"), g.Text(strings.Join(we.Values, ", "))),
+						)
+					})...),
+				)),
+			),
+		),
+	)
 }
 
 type wrap struct {
@@ -88,29 +268,92 @@ func (ww *wrap) Render(w io.Writer) error {
 
 func TaskRuns(td *model.TemplateData) g.Node {
 	return Div(
-		ID("details"), StyleAttr("display: flex;"),
+		ID("details"),
 
-		g.If(td.TaskRun != nil, &wrap{func() g.Node {
+		g.If(td.TaskRun != nil || td.PipelineRun != nil, &wrap{func() g.Node {
 			return RGroup(
+				PipelineRunHeader(td),
 				Div(
-					ID("tasks"), Class("ms-3 mt-3"),
-					StyleAttr("flex-shrink: 0; min-width: 300px;"),
-					Ul(
-						Class("menu bg-base-200 rounded-box"),
-						g.Group(g.Map(td.TaskRuns, taskRun(td, false))),
+					StyleAttr("display: flex;"),
+					Div(
+						ID("tasks"), Class("ms-3 mt-3"),
+						StyleAttr("flex-shrink: 0; min-width: 300px;"),
+						PipelineRunViewTabs(td, td.View, false),
+						Ul(
+							Class("menu bg-base-200 rounded-box"),
+							g.Group(g.Map(groupTaskRuns(td.TaskRuns), taskRunGroupNode(td))),
+							g.Group(childPipelineRuns(td)),
+							skippedTasks(td),
+						),
+					),
+					Div(
+						ID("taskrun-details"),
+						Class("ms-3 mt-3"),
+						StyleAttr("flex-grow: 5; height: 100%; display: flex; flex-direction: column;"),
+						g.If(
+							td.TaskRun != nil && (td.View == "" || td.View == stepsView),
+							&wrap{func() g.Node { return TaskRunDetails(false)(td) }},
+						),
 					),
-				),
-				Div(
-					ID("taskrun-details"),
-					Class("ms-3 mt-3"),
-					StyleAttr("flex-grow: 5; height: 100%; display: flex; flex-direction: column;"),
-					g.If(td.TaskRun != nil, &wrap{func() g.Node { return TaskRunDetails(false)(td) }}),
 				),
 			)
 		}}),
 	)
 }
 
+// withContainer appends the query params which select the kind of
+// container of Step to u.
+func withContainer(td *model.TemplateData, u string) string {
+	if td.ContainerKind == "" || td.ContainerKind == model.StepContainer {
+		return u
+	}
+	return appendQuery(u, "container="+td.ContainerKind)
+}
+
+// withState appends the query params which select the browsed
+// attempt of TaskRun and kind of container of Step to u.
+func withState(td *model.TemplateData, u string) string {
+	u = withContainer(td, u)
+	if td.Attempt == 0 {
+		return u
+	}
+	return appendQuery(u, "attempt="+strconv.Itoa(td.Attempt))
+}
+
+func appendQuery(u, query string) string {
+	if strings.Contains(u, "?") {
+		return u + "&" + query
+	}
+	return u + "?" + query
+}
+
+// conditionReason returns the reason of cond, if any.
+func conditionReason(cond *apis.Condition) string {
+	if cond == nil {
+		return ""
+	}
+	return cond.Reason
+}
+
+// containerDuration returns for how long a container has run, or zero
+// if it has not started yet.
+func containerDuration(state corev1.ContainerState) time.Duration {
+	switch {
+	case state.Terminated != nil:
+		return state.Terminated.FinishedAt.Sub(state.Terminated.StartedAt.Time)
+	case state.Running != nil:
+		return time.Since(state.Running.StartedAt.Time)
+	}
+	return 0
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
 func stepTabURL(data *model.TemplateData, taskRun, step, tab string) string {
 	if data.PipelineRun != nil {
 		return data.URLFor(
@@ -133,6 +376,25 @@ func stepTabURL(data *model.TemplateData, taskRun, step, tab string) string {
 	)
 }
 
+// taskRunURL returns the URL of a taskRun page without a selected step.
+func taskRunURL(data *model.TemplateData, taskRun string) string {
+	if data.PipelineRun != nil {
+		return data.URLFor(
+			"list-w-pipe-task",
+			data.PipelineRun.GetNamespace(),
+			"pipelineruns",
+			data.PipelineRun.GetName(),
+			taskRun,
+		)
+	}
+	return data.URLFor(
+		"list-w-details",
+		data.TaskRun.GetNamespace(),
+		"taskruns",
+		taskRun,
+	)
+}
+
 func stepURL(data *model.TemplateData, taskRun string, step string) string {
 	if data.PipelineRun != nil {
 		return data.URLFor(
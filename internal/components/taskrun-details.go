@@ -4,17 +4,22 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/status"
 	g "github.com/maragudk/gomponents"
 	htmx "github.com/maragudk/gomponents-htmx"
 	c "github.com/maragudk/gomponents/components"
 	. "github.com/maragudk/gomponents/html"
 	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"knative.dev/pkg/apis"
 )
 
 type stepDetail struct {
-	Name   string
+	Name string
+	// Route defaults to the lowercased Name
+	Route  string
 	Active bool
 }
 
@@ -29,6 +34,10 @@ func StepDetailsTabs(td *model.TemplateData, active string, outOfBand bool) g.No
 		{
 			Name: "Manifest",
 		},
+		{
+			Name:  "Details",
+			Route: "step-details",
+		},
 	}
 
 	noneActive := true
@@ -50,16 +59,20 @@ func StepDetailsTabs(td *model.TemplateData, active string, outOfBand bool) g.No
 			htmx.SwapOOB("true"),
 		),
 		g.Group(g.Map(stepDetails, func(sd *stepDetail) g.Node {
-			route := strings.ToLower(sd.Name)
+			tab := strings.ToLower(sd.Name)
+			route := sd.Route
+			if route == "" {
+				route = tab
+			}
 			return A(
 				c.Classes{
 					"tab":        true,
 					"tab-active": sd.Active,
 				},
-				htmx.Get(td.URLFor(route, td.Namespace, td.TaskRun.GetName(), td.Step)),
+				htmx.Get(withState(td, td.URLFor(route, td.Namespace, td.TaskRun.GetName(), td.Step))),
 				htmx.Target("#step-details-content"),
 				g.If(!outOfBand && sd.Active, htmx.Trigger("load")),
-				g.If(outOfBand || !sd.Active, htmx.PushURL(stepTabURL(td, td.TaskRun.GetName(), td.Step, route))),
+				g.If(outOfBand || !sd.Active, htmx.PushURL(withState(td, stepTabURL(td, td.TaskRun.GetName(), td.Step, tab)))),
 				g.Text(sd.Name),
 			)
 		})),
@@ -91,82 +104,351 @@ func RGroup(children ...g.Node) g.Node {
 
 type breadcrumb struct {
 	kind, name string
+
+	// getURL and pushURL, when set, make the breadcrumb a link
+	// loading getURL into the details pane. When only pushURL is set,
+	// it links to it instead.
+	getURL, pushURL string
 }
 
-func breadcrumbs(td *model.TemplateData) []breadcrumb {
+// pipelineBreadcrumbs lists the pipelineRuns the browsed pipelineRun,
+// or the parent of the browsed taskRun, is nested in, itself included.
+func pipelineBreadcrumbs(td *model.TemplateData) []breadcrumb {
 	var x []breadcrumb
+	link := func(pr *pipelinev1beta1.PipelineRun) breadcrumb {
+		return breadcrumb{
+			name: pr.GetName(),
+			kind: "PR",
+			pushURL: td.URLFor(
+				"list-w-details",
+				pr.GetNamespace(),
+				"pipelineruns",
+				pr.GetName(),
+			),
+		}
+	}
+	for _, pr := range td.Ancestry {
+		x = append(x, link(pr))
+	}
 	if td.PipelineRun != nil {
-		x = append(x, breadcrumb{name: td.PipelineRun.GetName(), kind: "PR"})
+		// scrolls up to the header rendered by PipelineRunHeader
+		x = append(x, breadcrumb{
+			name:    td.PipelineRun.GetName(),
+			kind:    "PR",
+			pushURL: "#pipelinerun-summary",
+		})
+	}
+	if pr := td.ParentPipelineRun; pr != nil {
+		x = append(x, link(pr))
 	}
+	return x
+}
+
+func breadcrumbs(td *model.TemplateData) []breadcrumb {
+	x := pipelineBreadcrumbs(td)
 	if td.TaskRun != nil {
 		x = append(x, breadcrumb{name: td.TaskRun.GetName(), kind: "TR"})
 	}
 	if td.Step != "" {
-		x = append(x, breadcrumb{name: td.Step, kind: "STEP"})
+		kind := "STEP"
+		switch td.ContainerKind {
+		case model.SidecarContainer:
+			kind = "SIDECAR"
+		case model.InitContainer:
+			kind = "INIT"
+		}
+		x = append(x, breadcrumb{name: td.Step, kind: kind})
 	}
 	return x
 }
 
+func breadcrumbsNode(crumbs []breadcrumb) g.Node {
+	return Div(
+		Class("text-sm breadcrumbs"),
+		Ul(
+			g.Map(
+				crumbs,
+				func(p breadcrumb) g.Node {
+					if p.pushURL != "" {
+						return Li(A(
+							Class("font-semibold"),
+							Href(p.pushURL),
+							g.If(p.getURL != "", g.Group([]g.Node{
+								htmx.Get(p.getURL),
+								htmx.Target("#taskrun-details"),
+								htmx.Swap("innerHTML"),
+								htmx.PushURL(p.pushURL),
+							})),
+							Div(Class("badge badge-info me-2"), g.Text(p.kind)),
+							g.Text(p.name),
+						))
+					}
+					return Li(Span(
+						Class("font-semibold"),
+						Div(Class("badge badge-info me-2"), g.Text(p.kind)),
+						g.Text(p.name),
+					))
+				},
+			)...,
+		),
+	)
+}
+
 func TaskRunDetails(outOfBand bool) func(*model.TemplateData) g.Node {
 	return func(td *model.TemplateData) g.Node {
 		if td.TaskRun == nil {
-			return g.Text("you are doing something wrong")
+			return g.Text("taskRun not found")
 		}
 		return RGroup(
 			g.If(outOfBand, taskRun(td, true)(td.TaskRun)),
+			g.If(outOfBand, PipelineRunViewTabs(td, stepsView, true)),
 			Div(
-				Div(
-					Class("text-sm breadcrumbs"),
-					Ul(
-						g.Map(
-							breadcrumbs(td),
-							func(p breadcrumb) g.Node {
-								return Li(Span(
-									Class("font-semibold"),
-									Div(Class("badge badge-info me-2"), g.Text(p.kind)),
-									g.Text(p.name),
-								))
-							},
-						)...,
-					),
-				),
+				breadcrumbsNode(breadcrumbs(td)),
+				pipelineContextLink(td),
+				attempts(td),
 				Div(
 					StyleAttr("max-height: 30vh; overflow-y: auto"),
-					Table(
-						Class("table table-zebra table-pin-rows"),
-						THead(Tr(Th(g.Text("Name")), Th(g.Text("Value")))),
-						TBody(
-							g.Map(
-								td.TaskRun.Spec.Params,
-								func(p pipelinev1beta1.Param) g.Node {
-									return Tr(
-										Td(
-											Class("select-all"),
-											g.Text(p.Name),
-										),
-										Td(
-											Class("select-all"),
-											g.Text(p.Value.StringVal),
-										),
-									)
-								},
-							)...,
-						),
-					),
-				),
-				Div(
-					StyleAttr("flex-grow: 1;"),
-					StepDetailsTabs(td, td.Tab, false),
-					Div(
-						Class("rounded-lg overflow-clip mt-2"),
-						ID("step-details-content"),
-					),
+					taskRunSource(td),
+					taskRunParams(td),
+					taskRunWorkspaces(td),
+					resultsTable(td, "tr-result-", taskRunResults(td.StatusOf(td.TaskRun))),
 				),
+				g.If(td.Step == "", &wrap{func() g.Node { return taskRunWaiting(td) }}),
+				g.If(td.Step != "", &wrap{func() g.Node {
+					return Div(
+						StyleAttr("flex-grow: 1;"),
+						StepDetailsTabs(td, td.Tab, false),
+						Div(
+							Class("rounded-lg overflow-clip mt-2"),
+							ID("step-details-content"),
+						),
+					)
+				}}),
 			),
 		)
 	}
 }
 
+// taskRunSource renders where the task of the browsed taskRun was
+// resolved from.
+func taskRunSource(td *model.TemplateData) g.Node {
+	var resolver pipelinev1beta1.ResolverRef
+	var bundle string
+	if ref := td.TaskRun.Spec.TaskRef; ref != nil {
+		resolver, bundle = ref.ResolverRef, ref.Bundle
+	}
+	return sourceTable(sourceFields(
+		td,
+		"tr-",
+		resolver,
+		bundle,
+		td.StatusOf(td.TaskRun).Provenance,
+	))
+}
+
+// pipelineContextLink links to the browsed taskRun, step and attempt
+// within the page of the pipelineRun owning it.
+func pipelineContextLink(td *model.TemplateData) g.Node {
+	pr := td.ParentPipelineRun
+	if pr == nil {
+		return nil
+	}
+	u := td.URLFor(
+		"list-w-pipe-task",
+		pr.GetNamespace(),
+		"pipelineruns",
+		pr.GetName(),
+		td.TaskRun.GetName(),
+	)
+	if td.Step != "" {
+		u = td.URLFor(
+			"list-w-pipe-details",
+			pr.GetNamespace(),
+			"pipelineruns",
+			pr.GetName(),
+			td.TaskRun.GetName(),
+			td.Step,
+		)
+	}
+	return A(
+		Class("link link-info text-sm mb-2 inline-block"),
+		Href(withState(td, u)),
+		g.Text("View in pipeline context"),
+	)
+}
+
+// taskRunParam is a param declared by a task, provided by the taskRun,
+// or both.
+type taskRunParam struct {
+	name        string
+	value       *pipelinev1beta1.ParamValue
+	defaultVal  *pipelinev1beta1.ParamValue
+	description string
+}
+
+// taskRunParams renders the params provided to the taskRun next to the
+// default and description the task declares for them.
+func taskRunParams(td *model.TemplateData) g.Node {
+	var params []*taskRunParam
+	byName := map[string]*taskRunParam{}
+	if spec := td.TaskSpecOf(td.TaskRun); spec != nil {
+		for _, ps := range spec.Params {
+			p := &taskRunParam{
+				name:        ps.Name,
+				defaultVal:  ps.Default,
+				description: ps.Description,
+			}
+			params = append(params, p)
+			byName[ps.Name] = p
+		}
+	}
+	for i, provided := range td.TaskRun.Spec.Params {
+		p, ok := byName[provided.Name]
+		if !ok {
+			p = &taskRunParam{name: provided.Name}
+			params = append(params, p)
+			byName[provided.Name] = p
+		}
+		p.value = &td.TaskRun.Spec.Params[i].Value
+	}
+	if len(params) == 0 {
+		return nil
+	}
+
+	return Table(
+		Class("table table-zebra table-pin-rows"),
+		THead(Tr(
+			Th(g.Text("Name")),
+			Th(g.Text("Value")),
+			Th(g.Text("Default")),
+			Th(g.Text("Description")),
+		)),
+		TBody(
+			g.Map(params, func(p *taskRunParam) g.Node {
+				prefix := "tr-param-" + p.name + "-"
+				var provided, declared g.Node
+				if p.value != nil {
+					provided = value(td, prefix, *p.value)
+				} else if p.defaultVal != nil {
+					provided = Span(Class("opacity-60 italic"), g.Text("default"))
+				}
+				if p.defaultVal != nil {
+					declared = value(td, prefix+"default-", *p.defaultVal)
+				}
+				return Tr(
+					ID(paramRowID(p.name)),
+					Td(
+						Class("select-all"),
+						g.Text(p.name),
+					),
+					Td(provided),
+					Td(declared),
+					Td(
+						Class("text-sm opacity-80"),
+						g.Text(p.description),
+					),
+				)
+			})...,
+		),
+	)
+}
+
+func taskRunResults(status *pipelinev1beta1.TaskRunStatus) []namedValue {
+	results := make([]namedValue, 0, len(status.TaskRunResults))
+	for _, r := range status.TaskRunResults {
+		results = append(results, namedValue{r.Name, r.Value})
+	}
+	return results
+}
+
+// attempts renders a selector between the attempts of TaskRun, when
+// it was retried.
+func attempts(td *model.TemplateData) g.Node {
+	retries := td.TaskRun.Status.RetriesStatus
+	if len(retries) == 0 {
+		return nil
+	}
+
+	statuses := make([]*pipelinev1beta1.TaskRunStatus, 0, len(retries)+1)
+	for i := range retries {
+		statuses = append(statuses, &retries[i])
+	}
+	statuses = append(statuses, &td.TaskRun.Status)
+
+	selected := td.Attempt
+	if selected <= 0 || selected > len(retries) {
+		selected = len(statuses)
+	}
+
+	buttons := make([]g.Node, 0, len(statuses))
+	for i, st := range statuses {
+		detailsURL, pushURL := attemptURLs(td, st)
+		attempt := i + 1
+		label := "Attempt " + strconv.Itoa(attempt)
+		if attempt == len(statuses) {
+			label += " (latest)"
+		}
+		var duration time.Duration
+		if st.StartTime != nil && st.CompletionTime != nil {
+			duration = st.CompletionTime.Sub(st.StartTime.Time)
+		}
+
+		query := "attempt=" + strconv.Itoa(attempt)
+		buttons = append(buttons, Button(
+			c.Classes{
+				"btn btn-sm join-item": true,
+				"btn-active":           attempt == selected,
+			},
+			htmx.Get(appendQuery(detailsURL, query)),
+			htmx.Target("#taskrun-details"),
+			htmx.Swap("innerHTML"),
+			htmx.PushURL(appendQuery(pushURL, query)),
+			statusIcon(status.OfTaskRun(st), conditionReason(st.GetCondition(apis.ConditionSucceeded))),
+			g.Text(label),
+			g.If(duration > 0, Span(
+				Class("text-xs opacity-60"),
+				g.Text(formatDuration(duration)),
+			)),
+		))
+	}
+
+	return Div(
+		Class("join mb-2"),
+		g.Group(buttons),
+	)
+}
+
+// attemptURLs returns the URLs of the details of an attempt of TaskRun
+// with the given status, staying on the browsed step when the attempt
+// has it and otherwise opening its first step, if any.
+func attemptURLs(td *model.TemplateData, st *pipelinev1beta1.TaskRunStatus) (detailsURL, pushURL string) {
+	step := td.Step
+	if td.ContainerKind == "" || td.ContainerKind == model.StepContainer {
+		step = ""
+		for _, ss := range st.Steps {
+			if ss.Name == td.Step || step == "" {
+				step = ss.Name
+			}
+		}
+	}
+
+	name := td.TaskRun.GetName()
+	if step == "" {
+		detailsURL = td.URLFor("details-wo-step", td.Namespace, name)
+		pushURL = taskRunURL(td, name)
+	} else {
+		detailsURL = td.URLFor("details-w-step", td.Namespace, name, step)
+		pushURL = withContainer(td, stepURL(td, name, step))
+	}
+	if td.PipelineRun != nil {
+		detailsURL = detailsURL + "?pipelineRun=" + td.PipelineRun.GetName()
+	}
+	if step != "" {
+		detailsURL = withContainer(td, detailsURL)
+	}
+	return detailsURL, pushURL
+}
+
 func stepTab(idx int, id string, active bool, content g.Node) g.Node {
 	return Li(
 		Class("nav-item"), Role("presentation"),
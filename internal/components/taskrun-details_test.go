@@ -0,0 +1,51 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAttemptsOfWaitingTaskRun(t *testing.T) {
+	tr := &pipelinev1beta1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "tr"},
+	}
+	// the latest attempt is waiting for its pod, so has no steps yet
+	tr.Status.RetriesStatus = []pipelinev1beta1.TaskRunStatus{{
+		TaskRunStatusFields: pipelinev1beta1.TaskRunStatusFields{
+			Steps: []pipelinev1beta1.StepState{{Name: "build"}, {Name: "test"}},
+		},
+	}}
+	td := &model.TemplateData{
+		Namespace: "ns",
+		TaskRun:   tr,
+		URLFor: func(name string, args ...interface{}) string {
+			parts := []string{name}
+			for _, a := range args {
+				parts = append(parts, fmt.Sprint(a))
+			}
+			return strings.Join(parts, "/")
+		},
+	}
+
+	var sb strings.Builder
+	if err := attempts(td).Render(&sb); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+	for _, want := range []string{
+		`hx-get="details-w-step/ns/tr/build?attempt=1"`,
+		`hx-get="details-wo-step/ns/tr?attempt=2"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("attempts missing %s in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `//?`) || strings.Contains(out, `/tr/?`) {
+		t.Errorf("attempts link to an empty step in:\n%s", out)
+	}
+}
@@ -0,0 +1,165 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/status"
+	g "github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+// taskRunWaiting explains why a taskRun has no steps to show: how far
+// its task was resolved, its conditions and why its pod is not running.
+func taskRunWaiting(td *model.TemplateData) g.Node {
+	tr := td.TaskRun
+	st := td.StatusOf(tr)
+	s := status.OfTaskRun(st)
+
+	message := "the taskRun has not been reconciled yet"
+	var reason string
+	if cond := st.GetCondition(apis.ConditionSucceeded); cond != nil {
+		reason = cond.Reason
+		if cond.Message != "" {
+			message = cond.Message
+		}
+	}
+
+	spec := "not resolved yet"
+	if tr.Status.TaskSpec != nil {
+		spec = "resolved, " + pluralize(len(tr.Status.TaskSpec.Steps), "step")
+	}
+
+	fields := []field{
+		{"Task", g.Text(taskDisplayName(tr))},
+		{"Reference", g.Text(taskReference(tr))},
+		{"Spec", g.Text(spec)},
+	}
+	if st.PodName == "" {
+		fields = append(fields, field{"Pod", g.Text("not created yet")})
+	} else {
+		fields = append(fields, field{"Pod", Span(Class("select-all"), g.Text(st.PodName))})
+	}
+	if td.Pod != nil {
+		fields = append(fields, field{"Pod phase", g.Text(string(td.Pod.Status.Phase))})
+	}
+
+	return Div(
+		Class("flex flex-col gap-3"),
+		Div(
+			Class("alert"),
+			statusIcon(s, reason),
+			Div(
+				H3(Class("font-semibold"), g.Text(s.Label())),
+				Div(Class("text-sm select-all whitespace-pre-wrap"), g.Text(message)),
+			),
+		),
+		Table(
+			Class("table table-sm"),
+			TBody(g.Map(fields, func(f field) g.Node {
+				return Tr(
+					Th(Class("w-48"), g.Text(f.name)),
+					Td(f.value),
+				)
+			})...),
+		),
+		conditionsTable(st.Conditions),
+		podWaiting(td.Pod),
+	)
+}
+
+// taskReference describes where the task of tr comes from.
+func taskReference(tr *pipelinev1beta1.TaskRun) string {
+	ref := tr.Spec.TaskRef
+	switch {
+	case ref != nil && ref.Resolver != "":
+		return "resolver " + string(ref.Resolver)
+	case ref != nil && ref.Bundle != "":
+		return "bundle " + ref.Bundle
+	case ref != nil:
+		kind := string(ref.Kind)
+		if kind == "" {
+			kind = string(pipelinev1beta1.NamespacedTaskKind)
+		}
+		return kind + " " + ref.Name
+	case tr.Spec.TaskSpec != nil:
+		return "embedded taskSpec"
+	}
+	return "-"
+}
+
+func conditionsTable(conds []apis.Condition) g.Node {
+	if len(conds) == 0 {
+		return nil
+	}
+	return Table(
+		Class("table table-zebra table-sm"),
+		THead(Tr(
+			Th(g.Text("Condition")),
+			Th(g.Text("Status")),
+			Th(g.Text("Reason")),
+			Th(g.Text("Message")),
+			Th(g.Text("Last transition")),
+		)),
+		TBody(g.Map(conds, func(cond apis.Condition) g.Node {
+			return Tr(
+				Td(g.Text(string(cond.Type))),
+				Td(g.Text(string(cond.Status))),
+				Td(g.Text(cond.Reason)),
+				Td(Class("select-all whitespace-pre-wrap"), g.Text(cond.Message)),
+				Td(g.Text(formatTime(&cond.LastTransitionTime.Inner))),
+			)
+		})...),
+	)
+}
+
+// podWaiting lists the pod conditions which do not hold yet and the
+// containers waiting to start, such as unschedulable pods or images
+// which cannot be pulled.
+func podWaiting(pod *corev1.Pod) g.Node {
+	if pod == nil {
+		return nil
+	}
+
+	var rows []g.Node
+	for _, cond := range pod.Status.Conditions {
+		if cond.Status == corev1.ConditionTrue {
+			continue
+		}
+		rows = append(rows, Tr(
+			Td(g.Text(string(cond.Type))),
+			Td(g.Text(cond.Reason)),
+			Td(Class("select-all whitespace-pre-wrap"), g.Text(cond.Message)),
+		))
+	}
+	statuses := append(
+		append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...),
+		pod.Status.ContainerStatuses...,
+	)
+	for _, cs := range statuses {
+		if cs.State.Waiting == nil {
+			continue
+		}
+		rows = append(rows, Tr(
+			Td(g.Text(strings.TrimPrefix(cs.Name, "step-"))),
+			Td(g.Text(cs.State.Waiting.Reason)),
+			Td(Class("select-all whitespace-pre-wrap"), g.Text(cs.State.Waiting.Message)),
+		))
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return Table(
+		Class("table table-zebra table-sm"),
+		THead(Tr(
+			Th(g.Text("Waiting on")),
+			Th(g.Text("Reason")),
+			Th(g.Text("Message")),
+		)),
+		TBody(rows...),
+	)
+}
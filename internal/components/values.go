@@ -0,0 +1,131 @@
+package components
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/syntax"
+	g "github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+// maxInlineValue is the length past which values are collapsed.
+const maxInlineValue = 80
+
+type namedValue struct {
+	Name  string
+	Value pipelinev1beta1.ParamValue
+}
+
+// resultsTable renders results in the same select-all table as params.
+func resultsTable(td *model.TemplateData, prefix string, results []namedValue) g.Node {
+	if len(results) == 0 {
+		return nil
+	}
+	return Table(
+		Class("table table-zebra table-pin-rows"),
+		THead(Tr(Th(g.Text("Result")), Th(g.Text("Value")))),
+		TBody(
+			g.Map(results, func(r namedValue) g.Node {
+				return Tr(
+					Td(
+						Class("select-all"),
+						g.Text(r.Name),
+					),
+					Td(value(td, prefix+r.Name+"-", r.Value)),
+				)
+			})...,
+		),
+	)
+}
+
+// value renders a param or result value. Short strings are rendered
+// as is, while long, JSON, array and object values are collapsed and
+// highlighted. prefix must be unique for each value in a page.
+func value(td *model.TemplateData, prefix string, v pipelinev1beta1.ParamValue) g.Node {
+	switch v.Type {
+	case pipelinev1beta1.ParamTypeArray:
+		js, _ := json.MarshalIndent(v.ArrayVal, "", "  ")
+		return expandable(td, prefix, pluralize(len(v.ArrayVal), "item"), string(js), "json")
+	case pipelinev1beta1.ParamTypeObject:
+		js, _ := json.MarshalIndent(v.ObjectVal, "", "  ")
+		return expandable(td, prefix, pluralize(len(v.ObjectVal), "key"), string(js), "json")
+	}
+
+	str := v.StringVal
+	if trimmed := strings.TrimSpace(str); strings.HasPrefix(trimmed, "{") ||
+		strings.HasPrefix(trimmed, "[") {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(trimmed), "", "  "); err == nil {
+			return expandable(td, prefix, truncate(trimmed), buf.String(), "json")
+		}
+	}
+	if len(str) > maxInlineValue || strings.Contains(str, "\n") {
+		return expandable(td, prefix, truncate(str), str, "")
+	}
+	return Span(Class("select-all"), g.Text(str))
+}
+
+func expandable(td *model.TemplateData, prefix, summary, content, lang string) g.Node {
+	return Details(
+		Summary(
+			Class("cursor-pointer font-mono"),
+			g.Text(summary),
+		),
+		Div(
+			Class("rounded-lg overflow-clip mt-1 text-sm"),
+			highlighted(td, prefix, content, lang),
+		),
+	)
+}
+
+// highlighted renders content through chroma, guessing its language
+// unless one is given.
+func highlighted(td *model.TemplateData, prefix, content, lang string) g.Node {
+	return g.NodeFunc(func(w io.Writer) error {
+		opts := []syntax.Option{
+			syntax.WithLinkPrefix(prefix),
+			syntax.WithLineNumbers(false),
+		}
+		if lang != "" {
+			opts = append(opts, syntax.WithLanguage(lang))
+		}
+		return syntax.FormatHTML(w, content, opts...)
+	})
+}
+
+func truncate(s string) string {
+	line, _, multiline := strings.Cut(s, "\n")
+	if short, ok := truncateRunes(line, maxInlineValue); ok {
+		return short + "…"
+	}
+	if multiline {
+		return line + " …"
+	}
+	return line
+}
+
+// truncateRunes cuts s to its first n runes, reporting whether it was
+// longer than that.
+func truncateRunes(s string, n int) (string, bool) {
+	i := 0
+	for at := range s {
+		if i == n {
+			return s[:at], true
+		}
+		i++
+	}
+	return s, false
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(n) + " " + noun + "s"
+}
@@ -0,0 +1,240 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	g "github.com/maragudk/gomponents"
+	. "github.com/maragudk/gomponents/html"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+// workspace is a workspace declared by a pipeline or task, bound by its
+// run, or both.
+type workspace struct {
+	name        string
+	description string
+	declared    bool
+	optional    bool
+	readOnly    bool
+	mountPath   string
+	binding     *pipelinev1beta1.WorkspaceBinding
+	usages      []workspaceUsage
+}
+
+// workspaceUsage is a pipelineTask workspace wired to a pipeline
+// workspace.
+type workspaceUsage struct {
+	pipelineTask string
+	taskRuns     []string
+	name         string
+	subPath      string
+}
+
+// workspaceIndex keeps workspaces in declaration order, adding the ones
+// only found in bindings or usages as they show up.
+type workspaceIndex struct {
+	workspaces []*workspace
+	byName     map[string]*workspace
+}
+
+func (idx *workspaceIndex) get(name string) *workspace {
+	if idx.byName == nil {
+		idx.byName = map[string]*workspace{}
+	}
+	ws, ok := idx.byName[name]
+	if !ok {
+		ws = &workspace{name: name}
+		idx.workspaces = append(idx.workspaces, ws)
+		idx.byName[name] = ws
+	}
+	return ws
+}
+
+func (idx *workspaceIndex) bind(bindings []pipelinev1beta1.WorkspaceBinding) {
+	for i := range bindings {
+		idx.get(bindings[i].Name).binding = &bindings[i]
+	}
+}
+
+// pipelineRunWorkspaces renders the workspaces of the browsed
+// pipelineRun along with the pipelineTasks using them.
+func pipelineRunWorkspaces(td *model.TemplateData) g.Node {
+	pr := td.PipelineRun
+	spec := pr.Status.PipelineSpec
+	if spec == nil {
+		spec = pr.Spec.PipelineSpec
+	}
+
+	var idx workspaceIndex
+	if spec != nil {
+		for _, decl := range spec.Workspaces {
+			ws := idx.get(decl.Name)
+			ws.declared = true
+			ws.description = decl.Description
+			ws.optional = decl.Optional
+		}
+	}
+	idx.bind(pr.Spec.Workspaces)
+	if spec != nil {
+		// matrixed pipelineTasks have a child per combination
+		taskRuns := map[string][]string{}
+		for _, cr := range pr.Status.ChildReferences {
+			taskRuns[cr.PipelineTaskName] = append(taskRuns[cr.PipelineTaskName], cr.Name)
+		}
+		for _, pt := range append(append([]pipelinev1beta1.PipelineTask{}, spec.Tasks...), spec.Finally...) {
+			for _, wb := range pt.Workspaces {
+				name := wb.Workspace
+				if name == "" {
+					name = wb.Name
+				}
+				ws := idx.get(name)
+				ws.usages = append(ws.usages, workspaceUsage{
+					pipelineTask: pt.Name,
+					taskRuns:     taskRuns[pt.Name],
+					name:         wb.Name,
+					subPath:      wb.SubPath,
+				})
+			}
+		}
+	}
+
+	return workspacesTable(idx.workspaces, "Used by", func(ws *workspace) g.Node {
+		if len(ws.usages) == 0 {
+			return Span(Class("opacity-60"), g.Text("-"))
+		}
+		return Ul(g.Group(g.Map(ws.usages, func(u workspaceUsage) g.Node {
+			var task g.Node = g.Text(u.pipelineTask)
+			if len(u.taskRuns) == 1 {
+				task = A(Class("link link-info"), Href(taskRunURL(td, u.taskRuns[0])), g.Text(u.pipelineTask))
+			}
+			var combinations []g.Node
+			if len(u.taskRuns) > 1 {
+				for i, name := range u.taskRuns {
+					combinations = append(combinations, A(
+						Class("badge badge-sm badge-ghost ms-1 link link-info"),
+						Href(taskRunURL(td, name)),
+						TitleAttr(name),
+						g.Text(fmt.Sprint(i+1)),
+					))
+				}
+			}
+			return Li(
+				task,
+				g.Group(combinations),
+				g.If(u.name != ws.name, g.Text(" as "+u.name)),
+				g.If(u.subPath != "", Span(
+					Class("badge badge-sm badge-ghost ms-2 font-mono"),
+					g.Text(u.subPath),
+				)),
+			)
+		})))
+	})
+}
+
+// taskRunWorkspaces renders the workspaces of the browsed taskRun along
+// with where they are mounted.
+func taskRunWorkspaces(td *model.TemplateData) g.Node {
+	tr := td.TaskRun
+	spec := td.TaskSpecOf(tr)
+
+	var idx workspaceIndex
+	if spec != nil {
+		for i, decl := range spec.Workspaces {
+			ws := idx.get(decl.Name)
+			ws.declared = true
+			ws.description = decl.Description
+			ws.optional = decl.Optional
+			ws.readOnly = decl.ReadOnly
+			ws.mountPath = spec.Workspaces[i].GetMountPath()
+		}
+	}
+	idx.bind(tr.Spec.Workspaces)
+
+	return workspacesTable(idx.workspaces, "Mount path", func(ws *workspace) g.Node {
+		if ws.mountPath == "" {
+			return Span(Class("opacity-60"), g.Text("-"))
+		}
+		return Span(Class("font-mono select-all"), g.Text(ws.mountPath))
+	})
+}
+
+// workspacesTable renders workspaces, flagging the ones left unbound
+// and bindings of undeclared workspaces.
+func workspacesTable(workspaces []*workspace, column string, cell func(*workspace) g.Node) g.Node {
+	if len(workspaces) == 0 {
+		return nil
+	}
+	return Table(
+		Class("table table-zebra table-pin-rows"),
+		THead(Tr(
+			Th(g.Text("Workspace")),
+			Th(g.Text("Bound to")),
+			Th(g.Text(column)),
+		)),
+		TBody(
+			g.Map(workspaces, func(ws *workspace) g.Node {
+				return Tr(
+					Td(
+						Span(Class("select-all"), g.Text(ws.name)),
+						g.If(ws.optional, Span(Class("badge badge-sm badge-ghost ms-2"), g.Text("optional"))),
+						g.If(ws.readOnly, Span(Class("badge badge-sm badge-ghost ms-2"), g.Text("read-only"))),
+						g.If(!ws.declared, Span(
+							Class("badge badge-sm badge-warning ms-2"),
+							g.Text("not declared"),
+						)),
+						g.If(ws.description != "", P(
+							Class("text-xs opacity-60"),
+							g.Text(ws.description),
+						)),
+					),
+					Td(workspaceBinding(ws)),
+					Td(cell(ws)),
+				)
+			})...,
+		),
+	)
+}
+
+func workspaceBinding(ws *workspace) g.Node {
+	if ws.binding == nil {
+		if ws.optional {
+			return Span(Class("opacity-60"), g.Text("unbound"))
+		}
+		return Span(Class("badge badge-error"), g.Text("unbound"))
+	}
+	kind, detail := bindingSource(*ws.binding)
+	return g.Group([]g.Node{
+		Span(Class("badge badge-ghost me-2"), g.Text(kind)),
+		Span(Class("select-all"), g.Text(detail)),
+		g.If(ws.binding.SubPath != "", Span(
+			Class("badge badge-sm badge-ghost ms-2 font-mono"),
+			g.Text(ws.binding.SubPath),
+		)),
+	})
+}
+
+// bindingSource describes the volume a workspace is bound to.
+func bindingSource(wb pipelinev1beta1.WorkspaceBinding) (kind, detail string) {
+	switch {
+	case wb.PersistentVolumeClaim != nil:
+		return "PVC", wb.PersistentVolumeClaim.ClaimName
+	case wb.VolumeClaimTemplate != nil:
+		detail := ""
+		if storage, ok := wb.VolumeClaimTemplate.Spec.Resources.Requests["storage"]; ok {
+			detail = storage.String()
+		}
+		return "volumeClaimTemplate", detail
+	case wb.ConfigMap != nil:
+		return "configMap", wb.ConfigMap.Name
+	case wb.Secret != nil:
+		return "secret", wb.Secret.SecretName
+	case wb.EmptyDir != nil:
+		return "emptyDir", ""
+	case wb.Projected != nil:
+		return "projected", pluralize(len(wb.Projected.Sources), "source")
+	case wb.CSI != nil:
+		return "CSI", wb.CSI.Driver
+	}
+	return "unknown", ""
+}
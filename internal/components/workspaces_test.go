@@ -0,0 +1,52 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWorkspacesOfMatrixedTask(t *testing.T) {
+	pr := &pipelinev1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pr"},
+	}
+	pr.Status.PipelineSpec = &pipelinev1beta1.PipelineSpec{
+		Workspaces: []pipelinev1beta1.PipelineWorkspaceDeclaration{{Name: "src"}},
+		Tasks: []pipelinev1beta1.PipelineTask{{
+			Name:       "build",
+			Workspaces: []pipelinev1beta1.WorkspacePipelineTaskBinding{{Name: "src"}},
+		}},
+	}
+	for _, name := range []string{"pr-build-0", "pr-build-1"} {
+		pr.Status.ChildReferences = append(pr.Status.ChildReferences, pipelinev1beta1.ChildStatusReference{
+			Name:             name,
+			PipelineTaskName: "build",
+		})
+	}
+	td := &model.TemplateData{
+		Namespace:   "ns",
+		PipelineRun: pr,
+		URLFor: func(name string, args ...interface{}) string {
+			parts := []string{name}
+			for _, a := range args {
+				parts = append(parts, fmt.Sprint(a))
+			}
+			return strings.Join(parts, "/")
+		},
+	}
+
+	var sb strings.Builder
+	if err := pipelineRunWorkspaces(td).Render(&sb); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+	for _, child := range []string{"pr-build-0", "pr-build-1"} {
+		if !strings.Contains(out, `href="list-w-pipe-task/ns/pipelineruns/pr/`+child+`"`) {
+			t.Errorf("workspaces missing a link to %s in:\n%s", child, out)
+		}
+	}
+}
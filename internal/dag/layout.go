@@ -0,0 +1,143 @@
+package dag
+
+import "sort"
+
+// Node is a vertex of the graph, depending on the nodes named by Deps.
+type Node struct {
+	Name string
+	Deps []string
+
+	// Final nodes run after every other node, they are laid out in
+	// an extra column past the last one.
+	Final bool
+}
+
+// Placement is the position of a node in the layered layout.
+type Placement struct {
+	Node
+	Column int
+	Row    int
+}
+
+type Edge struct {
+	From, To string
+}
+
+type Layout struct {
+	Nodes []Placement
+	Edges []Edge
+
+	// Columns and Rows are the amount of columns and the size of
+	// the tallest column of the layout.
+	Columns, Rows int
+}
+
+// Layered lays nodes out in columns so that every node is placed to the
+// right of all of its dependencies. Dependencies on unknown nodes are
+// ignored and so are cycles, which would make for an invalid pipeline.
+func Layered(nodes []Node) *Layout {
+	index := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		index[n.Name] = i
+	}
+
+	columns := make([]int, len(nodes))
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(nodes))
+
+	var visit func(i int) int
+	visit = func(i int) int {
+		switch state[i] {
+		case visiting:
+			return 0
+		case visited:
+			return columns[i]
+		}
+		state[i] = visiting
+		col := 0
+		for _, d := range nodes[i].Deps {
+			j, ok := index[d]
+			if !ok || nodes[j].Final != nodes[i].Final {
+				continue
+			}
+			if c := visit(j) + 1; c > col {
+				col = c
+			}
+		}
+		state[i] = visited
+		columns[i] = col
+		return col
+	}
+
+	lastColumn := -1
+	for i, n := range nodes {
+		if c := visit(i); !n.Final && c > lastColumn {
+			lastColumn = c
+		}
+	}
+	for i, n := range nodes {
+		if n.Final {
+			columns[i] += lastColumn + 1
+		}
+	}
+
+	l := &Layout{}
+	byColumn := map[int][]int{}
+	for i := range nodes {
+		c := columns[i]
+		byColumn[c] = append(byColumn[c], i)
+		if c+1 > l.Columns {
+			l.Columns = c + 1
+		}
+	}
+
+	// order each column by the average row of its dependencies
+	// to reduce edge crossings
+	rows := make([]float64, len(nodes))
+	for c := 0; c < l.Columns; c++ {
+		col := byColumn[c]
+		weight := make(map[int]float64, len(col))
+		for _, i := range col {
+			var sum float64
+			var count int
+			for _, d := range nodes[i].Deps {
+				if j, ok := index[d]; ok && columns[j] < c {
+					sum += rows[j]
+					count++
+				}
+			}
+			weight[i] = float64(i)
+			if count > 0 {
+				weight[i] = sum / float64(count)
+			}
+		}
+		sort.SliceStable(col, func(a, b int) bool {
+			return weight[col[a]] < weight[col[b]]
+		})
+		for r, i := range col {
+			rows[i] = float64(r)
+			l.Nodes = append(l.Nodes, Placement{
+				Node:   nodes[i],
+				Column: c,
+				Row:    r,
+			})
+		}
+		if len(col) > l.Rows {
+			l.Rows = len(col)
+		}
+	}
+
+	for _, n := range nodes {
+		for _, d := range n.Deps {
+			if _, ok := index[d]; ok {
+				l.Edges = append(l.Edges, Edge{From: d, To: n.Name})
+			}
+		}
+	}
+
+	return l
+}
@@ -0,0 +1,60 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLayered(t *testing.T) {
+	nodes := []Node{
+		{Name: "fetch"},
+		{Name: "lint", Deps: []string{"fetch"}},
+		{Name: "build", Deps: []string{"fetch"}},
+		{Name: "test", Deps: []string{"build", "unknown"}},
+		{Name: "deploy", Deps: []string{"lint", "test"}},
+		{Name: "cleanup", Final: true},
+	}
+
+	l := Layered(nodes)
+
+	gotColumns := map[string]int{}
+	for _, p := range l.Nodes {
+		gotColumns[p.Name] = p.Column
+	}
+	wantColumns := map[string]int{
+		"fetch":   0,
+		"lint":    1,
+		"build":   1,
+		"test":    2,
+		"deploy":  3,
+		"cleanup": 4,
+	}
+	if !reflect.DeepEqual(gotColumns, wantColumns) {
+		t.Errorf("Layered() columns = %v, want %v", gotColumns, wantColumns)
+	}
+
+	if l.Columns != 5 || l.Rows != 2 {
+		t.Errorf("Layered() size = %dx%d, want 5x2", l.Columns, l.Rows)
+	}
+
+	wantEdges := []Edge{
+		{"fetch", "lint"},
+		{"fetch", "build"},
+		{"build", "test"},
+		{"lint", "deploy"},
+		{"test", "deploy"},
+	}
+	if !reflect.DeepEqual(l.Edges, wantEdges) {
+		t.Errorf("Layered() edges = %v, want %v", l.Edges, wantEdges)
+	}
+}
+
+func TestLayeredIgnoresCycles(t *testing.T) {
+	l := Layered([]Node{
+		{Name: "a", Deps: []string{"b"}},
+		{Name: "b", Deps: []string{"a"}},
+	})
+	if len(l.Nodes) != 2 {
+		t.Errorf("Layered() placed %d nodes, want 2", len(l.Nodes))
+	}
+}
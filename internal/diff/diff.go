@@ -0,0 +1,220 @@
+// Package diff computes line based differences between two texts.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the operation turning one text into the other at a line.
+type Op rune
+
+const (
+	Equal  Op = ' '
+	Delete Op = '-'
+	Insert Op = '+'
+)
+
+// Line is a line of either text, along with how it changed.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// maxEdits bounds the edit distance Lines searches for, as the search
+// takes memory quadratic in it. Texts further apart are diffed as a
+// replacement of the lines between their common prefix and suffix.
+const maxEdits = 1000
+
+// Lines returns the shortest edit script from a to b, found with
+// Myers' O(ND) algorithm. Deletions precede insertions within a change.
+func Lines(a, b []string) []Line {
+	return lines(a, b, maxEdits)
+}
+
+func lines(a, b []string, maxEdits int) []Line {
+	// the common prefix and suffix need not be searched
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(a)+len(b)-prefix-suffix)
+	for _, l := range a[:prefix] {
+		lines = append(lines, Line{Equal, l})
+	}
+
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if edits, ok := myers(ma, mb, maxEdits); ok {
+		lines = append(lines, edits...)
+	} else {
+		for _, l := range ma {
+			lines = append(lines, Line{Delete, l})
+		}
+		for _, l := range mb {
+			lines = append(lines, Line{Insert, l})
+		}
+	}
+
+	for _, l := range a[len(a)-suffix:] {
+		lines = append(lines, Line{Equal, l})
+	}
+	return lines
+}
+
+// myers returns the shortest edit script from a to b, or false when it
+// takes more than maxEdits insertions and deletions.
+func myers(a, b []string, maxEdits int) ([]Line, bool) {
+	n, m := len(a), len(b)
+	limit := n + m
+	if limit > maxEdits {
+		limit = maxEdits
+	}
+	// v[k+offset] is the furthest x reached on diagonal k = x - y, and
+	// trace[d] a copy of v for diagonals -d..d after d edits
+	offset := limit + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			// move down, inserting, or right, deleting
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+				return backtrack(a, b, trace), true
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+	return nil, false
+}
+
+// backtrack walks the trace of myers back from the end of both texts,
+// and returns the edit script it found.
+func backtrack(a, b []string, trace [][]int) []Line {
+	var lines []Line
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1]
+		at := func(k int) int { return prev[k+d-1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			lines = append(lines, Line{Equal, a[x]})
+		}
+		if x == prevX {
+			y--
+			lines = append(lines, Line{Insert, b[y]})
+		} else {
+			x--
+			lines = append(lines, Line{Delete, a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		lines = append(lines, Line{Equal, a[x]})
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+// Unified returns the unified diff from a to b, showing context
+// unchanged lines around each change. It returns an empty string when
+// both texts are equal.
+func Unified(fromName, toName, a, b string, context int) string {
+	lines := Lines(split(a), split(b))
+
+	// hunks are [start, end) ranges of lines, merged when their
+	// context overlaps
+	var hunks [][2]int
+	for i, l := range lines {
+		if l.Op == Equal {
+			continue
+		}
+		start, end := i-context, i+context+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(lines) {
+			end = len(lines)
+		}
+		if last := len(hunks) - 1; last >= 0 && start <= hunks[last][1] {
+			hunks[last][1] = end
+			continue
+		}
+		hunks = append(hunks, [2]int{start, end})
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	// from and to are the number of lines of a and b preceding each line
+	from := make([]int, len(lines)+1)
+	to := make([]int, len(lines)+1)
+	for i, l := range lines {
+		from[i+1], to[i+1] = from[i], to[i]
+		if l.Op != Insert {
+			from[i+1]++
+		}
+		if l.Op != Delete {
+			to[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	for _, h := range hunks {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(from[h[0]], from[h[1]]-from[h[0]]),
+			hunkRange(to[h[0]], to[h[1]]-to[h[0]]),
+		)
+		for _, l := range lines[h[0]:h[1]] {
+			sb.WriteRune(rune(l.Op))
+			sb.WriteString(l.Text)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// hunkRange formats the range of a hunk starting after the given
+// number of lines. Empty ranges refer to the line preceding them.
+func hunkRange(preceding, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", preceding)
+	}
+	return fmt.Sprintf("%d,%d", preceding+1, count)
+}
+
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,137 @@
+package diff
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want []Line
+	}{
+		{"equal", []string{"a", "b"}, []string{"a", "b"}, []Line{
+			{Equal, "a"}, {Equal, "b"},
+		}},
+		{"empty", nil, []string{"a"}, []Line{
+			{Insert, "a"},
+		}},
+		{"replaced", []string{"a", "b", "c"}, []string{"a", "x", "c"}, []Line{
+			{Equal, "a"}, {Delete, "b"}, {Insert, "x"}, {Equal, "c"},
+		}},
+		{"moved", []string{"a", "b", "c", "d"}, []string{"b", "c", "a", "d"}, []Line{
+			{Delete, "a"}, {Equal, "b"}, {Equal, "c"}, {Insert, "a"}, {Equal, "d"},
+		}},
+	}
+	for _, tt := range tests {
+		if got := Lines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLinesShortest(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	text := func() []string {
+		lines := make([]string, rnd.Intn(12))
+		for i := range lines {
+			lines[i] = strconv.Itoa(rnd.Intn(4))
+		}
+		return lines
+	}
+	for i := 0; i < 500; i++ {
+		a, b := text(), text()
+		lines := Lines(a, b)
+
+		var gotA, gotB []string
+		edits := 0
+		for _, l := range lines {
+			if l.Op != Insert {
+				gotA = append(gotA, l.Text)
+			}
+			if l.Op != Delete {
+				gotB = append(gotB, l.Text)
+			}
+			if l.Op != Equal {
+				edits++
+			}
+		}
+		if len(gotA) != len(a) || len(gotB) != len(b) ||
+			(len(a) > 0 && !reflect.DeepEqual(gotA, a)) ||
+			(len(b) > 0 && !reflect.DeepEqual(gotB, b)) {
+			t.Fatalf("Lines(%v, %v) = %v does not turn one into the other", a, b, lines)
+		}
+		if want := len(a) + len(b) - 2*lcsLength(a, b); edits != want {
+			t.Fatalf("Lines(%v, %v) = %v takes %d edits, want %d", a, b, lines, edits, want)
+		}
+	}
+}
+
+// lcsLength is the length of the longest common subsequence of a and b.
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] > cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestLinesTooFarApart(t *testing.T) {
+	a := []string{"name: a", "1", "2", "3", "end"}
+	b := []string{"name: a", "x", "2", "y", "end"}
+	want := []Line{
+		{Equal, "name: a"},
+		{Delete, "1"}, {Delete, "2"}, {Delete, "3"},
+		{Insert, "x"}, {Insert, "2"}, {Insert, "y"},
+		{Equal, "end"},
+	}
+	if got := lines(a, b, 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	if got := Unified("a", "b", "x\ny\n", "x\ny\n", 3); got != "" {
+		t.Errorf("equal texts: got %q, want no diff", got)
+	}
+
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\nten\n"
+	want := `--- a
++++ b
+@@ -2,3 +2,3 @@
+ 2
+-3
++three
+ 4
+@@ -9,1 +9,2 @@
+ 9
++ten
+`
+	if got := Unified("a", "b", a, b, 1); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	want = `--- a
++++ b
+@@ -0,0 +1,1 @@
++x
+`
+	if got := Unified("a", "b", "", "x\n", 0); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/components"
+	"github.com/cezarguimaraes/tkn-dash/internal/diff"
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/tekton"
+	"github.com/labstack/echo/v4"
+	g "github.com/maragudk/gomponents"
+	"sigs.k8s.io/yaml"
+)
+
+// diffContext is the number of unchanged lines shown around changes.
+const diffContext = 3
+
+// Compare renders the comparison of two runs of the same resource,
+// possibly from different namespaces.
+func Compare() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tc := c.(*tekton.Context)
+		td := &model.TemplateData{}
+		if err := tc.BindTemplateData(td); err != nil {
+			return err
+		}
+		if td.Left == nil || td.Right == nil {
+			return echo.NewHTTPError(http.StatusNotFound, "run not found")
+		}
+
+		left, right := td.Left.Object().GetName(), td.Right.Object().GetName()
+		specDiff, err := yamlDiff(left, right, runSpec(td.Left), runSpec(td.Right))
+		if err != nil {
+			return err
+		}
+		manifestDiff, err := yamlDiff(left, right, runManifest(td.Left), runManifest(td.Right))
+		if err != nil {
+			return err
+		}
+
+		c.Response().WriteHeader(http.StatusOK)
+		return components.Shell(func(td *model.TemplateData) g.Node {
+			return components.Compare(td, specDiff, manifestDiff)
+		})(td).Render(c.Response())
+	}
+}
+
+// CompareSelection redirects to the comparison of the two runs selected
+// in the explorer list, sent as namespace/name ?compare query params.
+func CompareSelection() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		selected := c.QueryParams()["compare"]
+		if len(selected) != 2 {
+			return c.String(http.StatusOK, "select two runs to compare")
+		}
+		lns, left, _ := strings.Cut(selected[0], "/")
+		rns, right, _ := strings.Cut(selected[1], "/")
+		c.Response().Header().Set(
+			"HX-Redirect",
+			c.Echo().Reverse("compare", lns, c.Param("resource"), left, rns, right),
+		)
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func runSpec(r *model.Run) interface{} {
+	if r.PipelineRun != nil {
+		return r.PipelineRun.Status.PipelineSpec
+	}
+	return r.TaskRuns[0].Status.TaskSpec
+}
+
+func runManifest(r *model.Run) interface{} {
+	if r.PipelineRun != nil {
+		pr := r.PipelineRun.DeepCopy()
+		omitLargeFields(&pr.ObjectMeta)
+		return pr
+	}
+	tr := r.TaskRuns[0].DeepCopy()
+	omitLargeFields(&tr.ObjectMeta)
+	return tr
+}
+
+// yamlDiff returns the unified diff between the YAML of two objects.
+func yamlDiff(leftName, rightName string, left, right interface{}) (string, error) {
+	l, err := yaml.Marshal(left)
+	if err != nil {
+		return "", err
+	}
+	r, err := yaml.Marshal(right)
+	if err != nil {
+		return "", err
+	}
+	return diff.Unified(leftName, rightName, string(l), string(r), diffContext), nil
+}
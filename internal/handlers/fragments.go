@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/pkg/lru"
+	"github.com/labstack/echo/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FragmentCache caches fragments rendered out of tekton objects, such as
+// highlighted scripts and manifests, until the objects change. Clients
+// revalidate them through ETags derived from the same keys, so they are
+// answered without rendering, even after eviction.
+//
+// Syntax styles are applied by their stylesheet rather than inlined, so
+// fragments are shared between styles. A nil *FragmentCache renders
+// every request.
+type FragmentCache struct {
+	lru *lru.Cache[string, fragment]
+	// maxFragment bounds the size of cached fragments, so a few large
+	// manifests or logs do not evict every other fragment
+	maxFragment int
+	// generation changes the ETags of every restart, as rendering may
+	// have changed in between
+	generation  string
+	notModified atomic.Uint64
+}
+
+type fragment struct {
+	body        []byte
+	contentType string
+}
+
+// FragmentCacheStats reports how effective a FragmentCache is.
+type FragmentCacheStats struct {
+	lru.Stats
+	HitRate     float64
+	Entries     int
+	Bytes       int64
+	NotModified uint64
+}
+
+// maxFragmentShare is the share of the cache a single fragment may
+// take at most.
+const maxFragmentShare = 16
+
+// NewFragmentCache returns a cache holding fragments up to a total of
+// maxBytes.
+func NewFragmentCache(maxBytes int64) *FragmentCache {
+	return &FragmentCache{
+		lru: lru.New[string](maxBytes, func(f fragment) int64 {
+			return int64(len(f.body))
+		}),
+		maxFragment: int(maxBytes / maxFragmentShare),
+		generation:  strconv.FormatInt(time.Now().UnixNano(), 36),
+	}
+}
+
+// Stats returns the current statistics of fc.
+func (fc *FragmentCache) Stats() FragmentCacheStats {
+	stats := fc.lru.Stats()
+	return FragmentCacheStats{
+		Stats:       stats,
+		HitRate:     stats.HitRate(),
+		Entries:     fc.lru.Len(),
+		Bytes:       fc.lru.Size(),
+		NotModified: fc.notModified.Load(),
+	}
+}
+
+// FragmentStats serves the statistics of fc as JSON.
+func FragmentStats(fc *FragmentCache) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, fc.Stats())
+	}
+}
+
+// renderFunc writes a fragment to buf. It returns false when the
+// fragment must not be cached, e.g. because it is incomplete.
+type renderFunc func(buf *bytes.Buffer) (cacheable bool, err error)
+
+// serve answers c with the fragment of objs identified by step and the
+// request URI, as well as the values of cookies it depends on. The
+// fragment is cached as long as the UID and resourceVersion of objs do
+// not change. modified is when they last changed, or zero if unknown.
+func (fc *FragmentCache) serve(
+	c echo.Context,
+	objs []metav1.Object,
+	step string,
+	cookies []string,
+	modified time.Time,
+	render renderFunc,
+) error {
+	if fc == nil {
+		return renderTo(c, render)
+	}
+
+	req := c.Request()
+	var key []string
+	for _, obj := range objs {
+		key = append(key,
+			string(obj.GetUID()),
+			obj.GetNamespace(),
+			obj.GetName(),
+			obj.GetResourceVersion(),
+		)
+	}
+	key = append(key, step, req.URL.RequestURI())
+	for _, name := range cookies {
+		if cookie, err := c.Cookie(name); err == nil {
+			key = append(key, name+"="+cookie.Value)
+		}
+	}
+	sum := sha256.Sum256([]byte(strings.Join(append(key, fc.generation), "\x00")))
+	etag := `"` + hex.EncodeToString(sum[:12]) + `"`
+
+	h := c.Response().Header()
+	h.Set(echo.HeaderCacheControl, "no-cache")
+	h.Add(echo.HeaderVary, "Cookie")
+	if !modified.IsZero() {
+		h.Set(echo.HeaderLastModified, modified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(req, etag, modified) {
+		fc.notModified.Add(1)
+		h.Set("ETag", etag)
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	if f, ok := fc.lru.Get(etag); ok {
+		h.Set("ETag", etag)
+		return c.Blob(http.StatusOK, f.contentType, f.body)
+	}
+
+	var buf bytes.Buffer
+	cacheable, err := render(&buf)
+	if err != nil {
+		return err
+	}
+	if cacheable {
+		// fragments too large to cache can still be revalidated
+		if buf.Len() <= fc.maxFragment {
+			fc.lru.Add(etag, fragment{buf.Bytes(), echo.MIMETextHTMLCharsetUTF8})
+		}
+		h.Set("ETag", etag)
+	} else {
+		h.Del(echo.HeaderLastModified)
+	}
+	return c.HTMLBlob(http.StatusOK, buf.Bytes())
+}
+
+// renderTo renders a fragment straight to the response of c.
+func renderTo(c echo.Context, render renderFunc) error {
+	var buf bytes.Buffer
+	if _, err := render(&buf); err != nil {
+		return err
+	}
+	return c.HTMLBlob(http.StatusOK, buf.Bytes())
+}
+
+// scriptSources returns the taskRun of td along with the pipelineRun its
+// script substitutions resolve against, if any, and when they last
+// changed.
+func scriptSources(td *model.TemplateData) ([]metav1.Object, time.Time) {
+	modified := completedAt(td.StatusOf(td.TaskRun).CompletionTime)
+	pr := td.PipelineRun
+	if pr == nil {
+		pr = td.ParentPipelineRun
+	}
+	if pr == nil {
+		return []metav1.Object{td.TaskRun}, modified
+	}
+	// results of other taskRuns are substituted until pr completes
+	if prModified := completedAt(pr.Status.CompletionTime); prModified.IsZero() || modified.IsZero() {
+		modified = time.Time{}
+	} else if prModified.After(modified) {
+		modified = prModified
+	}
+	return []metav1.Object{td.TaskRun, pr}, modified
+}
+
+// completedAt is the time objects which completed at t last changed,
+// or zero when they did not complete yet and may still change.
+func completedAt(t *metav1.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.Time
+}
+
+// notModified tells whether the client already has the fragment with
+// etag, last modified at modified, as per the conditional headers of req.
+func notModified(req *http.Request, etag string, modified time.Time) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if modified.IsZero() {
+		return false
+	}
+	ims, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(ims)
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/labstack/echo/v4"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fragmentRequest struct {
+	target  string
+	obj     *metav1.ObjectMeta
+	parent  *metav1.ObjectMeta
+	step    string
+	headers map[string]string
+}
+
+// serveFragment answers req through fc, counting renders in renders.
+func serveFragment(
+	t *testing.T,
+	fc *FragmentCache,
+	req fragmentRequest,
+	modified time.Time,
+	cacheable bool,
+	renders *int,
+) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, req.target, nil)
+	for k, v := range req.headers {
+		r.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(r, rec)
+	objs := []metav1.Object{req.obj}
+	if req.parent != nil {
+		objs = append(objs, req.parent)
+	}
+	err := fc.serve(c, objs, req.step, []string{"pref"}, modified, func(buf *bytes.Buffer) (bool, error) {
+		*renders++
+		buf.WriteString("fragment")
+		return cacheable, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+func TestFragmentCacheKeys(t *testing.T) {
+	fc := NewFragmentCache(1 << 20)
+	obj := func(rv string) *metav1.ObjectMeta {
+		return &metav1.ObjectMeta{Namespace: "ns", Name: "tr", UID: "uid", ResourceVersion: rv}
+	}
+	base := fragmentRequest{target: "/script/ns/tr/step/build", obj: obj("1"), step: "build"}
+	variants := map[string]fragmentRequest{
+		"resourceVersion": {target: base.target, obj: obj("2"), step: base.step},
+		"step":            {target: base.target, obj: base.obj, step: "test"},
+		"query":           {target: base.target + "?language=python", obj: base.obj, step: base.step},
+		"pipelineRun": {target: base.target, obj: base.obj, step: base.step, parent: &metav1.ObjectMeta{
+			Namespace: "ns", Name: "pr", UID: "pr-uid", ResourceVersion: "1",
+		}},
+		"cookie": {target: base.target, obj: base.obj, step: base.step, headers: map[string]string{
+			"Cookie": "pref=true",
+		}},
+	}
+
+	var renders int
+	etag := serveFragment(t, fc, base, time.Time{}, true, &renders).Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag sent for a cacheable fragment")
+	}
+	if again := serveFragment(t, fc, base, time.Time{}, true, &renders).Header().Get("ETag"); again != etag {
+		t.Errorf("ETag changed from %s to %s for the same fragment", etag, again)
+	}
+	if renders != 1 {
+		t.Errorf("rendered %d times, want 1 as the second request is a hit", renders)
+	}
+	for name, req := range variants {
+		if got := serveFragment(t, fc, req, time.Time{}, true, &renders).Header().Get("ETag"); got == etag {
+			t.Errorf("ETag did not change with the %s", name)
+		}
+	}
+	if stats := fc.Stats(); stats.Hits != 1 || stats.Misses != 1+uint64(len(variants)) {
+		t.Errorf("Stats() = %+v, want 1 hit and %d misses", stats, 1+len(variants))
+	}
+}
+
+func TestFragmentCacheRevalidation(t *testing.T) {
+	fc := NewFragmentCache(1 << 20)
+	modified := time.Date(2023, 8, 1, 10, 2, 0, 0, time.UTC)
+	req := fragmentRequest{
+		target: "/manifest/ns/tr/step/build",
+		obj:    &metav1.ObjectMeta{Namespace: "ns", Name: "tr", UID: "uid", ResourceVersion: "1"},
+	}
+
+	var renders int
+	rec := serveFragment(t, fc, req, modified, true, &renders)
+	etag := rec.Header().Get("ETag")
+	if got := rec.Header().Get(echo.HeaderLastModified); got != modified.Format(http.TimeFormat) {
+		t.Errorf("Last-Modified = %q, want %q", got, modified.Format(http.TimeFormat))
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"matching etag", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"weak etag list", map[string]string{"If-None-Match": `"other", W/` + etag}, http.StatusNotModified},
+		{"stale etag", map[string]string{"If-None-Match": `"other"`}, http.StatusOK},
+		{"etag over date", map[string]string{
+			"If-None-Match":     `"other"`,
+			"If-Modified-Since": modified.Format(http.TimeFormat),
+		}, http.StatusOK},
+		{"not modified since", map[string]string{
+			"If-Modified-Since": modified.Add(time.Hour).Format(http.TimeFormat),
+		}, http.StatusNotModified},
+		{"modified since", map[string]string{
+			"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat),
+		}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		req.headers = tt.headers
+		rec := serveFragment(t, fc, req, modified, true, &renders)
+		if rec.Code != tt.want {
+			t.Errorf("%s: got status %d, want %d", tt.name, rec.Code, tt.want)
+		}
+		if tt.want == http.StatusNotModified && rec.Body.Len() > 0 {
+			t.Errorf("%s: 304 sent with a body", tt.name)
+		}
+	}
+	if renders != 1 {
+		t.Errorf("rendered %d times, want 1", renders)
+	}
+	if got := fc.Stats().NotModified; got != 3 {
+		t.Errorf("NotModified = %d, want 3", got)
+	}
+}
+
+func TestFragmentCacheUncacheable(t *testing.T) {
+	fc := NewFragmentCache(1 << 20)
+	req := fragmentRequest{
+		target: "/log/ns/tr/step/build",
+		obj:    &metav1.ObjectMeta{Namespace: "ns", Name: "tr", UID: "uid", ResourceVersion: "1"},
+	}
+
+	var renders int
+	for i := 0; i < 2; i++ {
+		rec := serveFragment(t, fc, req, time.Now(), false, &renders)
+		if rec.Header().Get("ETag") != "" || rec.Header().Get(echo.HeaderLastModified) != "" {
+			t.Errorf("validators sent for an uncacheable fragment: %v", rec.Header())
+		}
+	}
+	if renders != 2 {
+		t.Errorf("rendered %d times, want 2", renders)
+	}
+	if fc.Stats().Entries != 0 {
+		t.Errorf("uncacheable fragment was cached")
+	}
+
+	var nilCache *FragmentCache
+	if rec := serveFragment(t, nilCache, req, time.Time{}, true, &renders); rec.Body.String() != "fragment" {
+		t.Errorf("nil cache served %q", rec.Body.String())
+	}
+}
+
+func TestContainerTerminated(t *testing.T) {
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	terminated := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}
+	status := &pipelinev1beta1.TaskRunStatus{
+		TaskRunStatusFields: pipelinev1beta1.TaskRunStatusFields{
+			Steps: []pipelinev1beta1.StepState{
+				{Name: "build", ContainerState: terminated},
+				{Name: "test", ContainerState: running},
+			},
+			Sidecars: []pipelinev1beta1.SidecarState{
+				{Name: "db", ContainerState: running},
+			},
+		},
+	}
+	tests := []struct {
+		kind, step string
+		want       bool
+	}{
+		{model.StepContainer, "build", true},
+		{model.StepContainer, "test", false},
+		{model.SidecarContainer, "db", false},
+		{model.InitContainer, "prepare", false},
+	}
+	for _, tt := range tests {
+		td := &model.TemplateData{ContainerKind: tt.kind, Step: tt.step}
+		if got := containerTerminated(td, status); got != tt.want {
+			t.Errorf("%s %s: got %v, want %v", tt.kind, tt.step, got, tt.want)
+		}
+	}
+
+	status.CompletionTime = &metav1.Time{}
+	td := &model.TemplateData{ContainerKind: model.StepContainer, Step: "test"}
+	if !containerTerminated(td, status) {
+		t.Errorf("containers of completed taskRuns are not terminated")
+	}
+}
+
+func TestScriptSources(t *testing.T) {
+	trDone := metav1.NewTime(time.Date(2023, 8, 1, 10, 2, 0, 0, time.UTC))
+	prDone := metav1.NewTime(trDone.Add(time.Minute))
+	tr := &pipelinev1beta1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: "tr"}}
+	tr.Status.CompletionTime = &trDone
+	pr := &pipelinev1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr"}}
+
+	objs, modified := scriptSources(&model.TemplateData{TaskRun: tr})
+	if len(objs) != 1 || !modified.Equal(trDone.Time) {
+		t.Errorf("without a pipelineRun: got %d objects modified at %v", len(objs), modified)
+	}
+
+	objs, modified = scriptSources(&model.TemplateData{TaskRun: tr, ParentPipelineRun: pr})
+	if len(objs) != 2 || objs[1].GetName() != "pr" || !modified.IsZero() {
+		t.Errorf("running pipelineRun: got %d objects modified at %v", len(objs), modified)
+	}
+
+	pr.Status.CompletionTime = &prDone
+	if _, modified = scriptSources(&model.TemplateData{TaskRun: tr, PipelineRun: pr}); !modified.Equal(prDone.Time) {
+		t.Errorf("completed pipelineRun: modified at %v, want %v", modified, prDone.Time)
+	}
+}
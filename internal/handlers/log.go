@@ -2,20 +2,23 @@ package handlers
 
 import (
 	"bytes"
-	"html"
-	"io"
+	"context"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/cezarguimaraes/tkn-dash/internal/components"
 	"github.com/cezarguimaraes/tkn-dash/internal/model"
 	"github.com/cezarguimaraes/tkn-dash/internal/tekton"
+	"github.com/cezarguimaraes/tkn-dash/pkg/logs"
 	"github.com/labstack/echo/v4"
-	v1 "k8s.io/api/core/v1"
-	clientset "k8s.io/client-go/kubernetes"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // TODO: poll from htmx until container finishes
-func StepLog(cs *clientset.Clientset) echo.HandlerFunc {
+func StepLog(lp logs.Provider, fc *FragmentCache) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		tc := c.(*tekton.Context)
 		td := &model.TemplateData{}
@@ -23,36 +26,207 @@ func StepLog(cs *clientset.Clientset) echo.HandlerFunc {
 			return err
 		}
 
-		components.StepDetailsTabs(td, "log", true).
-			Render(c.Response())
+		prefs := logPreferences(c)
 
-		if cs == nil {
-			return c.String(
-				http.StatusOK,
-				"logs unavailable: tkn-dash initialized from files",
+		status := td.StatusOf(td.TaskRun)
+		var prefCookies []string
+		for _, pref := range []string{
+			components.LogTimestampsPref,
+			components.LogRelativePref,
+			components.LogWrapPref,
+		} {
+			prefCookies = append(prefCookies, logPrefCookiePrefix+pref)
+		}
+		// logs of running containers still grow, so only those of
+		// terminated ones are cached
+		terminated := containerTerminated(td, status)
+		modified := completedAt(status.CompletionTime)
+		if !terminated {
+			modified = time.Time{}
+		}
+		return fc.serve(c, []metav1.Object{td.TaskRun}, td.ContainerName(), prefCookies, modified, func(buf *bytes.Buffer) (bool, error) {
+			lines, err := fetchLines(
+				c.Request().Context(),
+				lp,
+				td.Namespace,
+				status,
+				td.ContainerName(),
 			)
+			if err != nil {
+				tc.Log.V(2).Info("failed to retrieve logs", "error", err)
+			}
+			return terminated && err == nil,
+				components.StepLog(td, prefs, lines, err).Render(buf)
+		})
+	}
+}
+
+// containerTerminated tells whether the container running the step of td
+// terminated in the taskRun attempt with the given status.
+func containerTerminated(td *model.TemplateData, status *pipelinev1beta1.TaskRunStatus) bool {
+	if status.CompletionTime != nil {
+		return true
+	}
+	switch td.ContainerKind {
+	case model.StepContainer:
+		for _, ss := range status.Steps {
+			if ss.Name == td.Step {
+				return ss.Terminated != nil
+			}
+		}
+	case model.SidecarContainer:
+		for _, sc := range status.Sidecars {
+			if sc.Name == td.Step {
+				return sc.Terminated != nil
+			}
 		}
+	}
+	return false
+}
 
-		req := cs.CoreV1().Pods(td.Namespace).GetLogs(
-			td.TaskRun.Status.PodName,
-			&v1.PodLogOptions{
-				Container: "step-" + td.Step,
-			},
-		)
-		rc, err := req.Stream(c.Request().Context())
-		if err != nil {
-			return err
+const (
+	logPrefCookiePrefix = "log-"
+	prefCookieMaxAge    = 365 * 24 * 60 * 60
+)
+
+// logPreferences reads the user's log preferences from their cookies.
+// Preferences sent as query params take precedence and are persisted,
+// so they are kept when switching between tabs and steps.
+func logPreferences(c echo.Context) model.LogPreferences {
+	prefs := model.LogPreferences{
+		Wrap: true,
+	}
+	for pref, value := range map[string]*bool{
+		components.LogTimestampsPref: &prefs.Timestamps,
+		components.LogRelativePref:   &prefs.Relative,
+		components.LogWrapPref:       &prefs.Wrap,
+	} {
+		name := logPrefCookiePrefix + pref
+		if cookie, err := c.Cookie(name); err == nil {
+			if b, err := strconv.ParseBool(cookie.Value); err == nil {
+				*value = b
+			}
 		}
-		defer rc.Close()
 
-		var buf bytes.Buffer
-		_, err = io.Copy(&buf, rc)
+		b, err := strconv.ParseBool(c.QueryParam(pref))
 		if err != nil {
+			continue
+		}
+		*value = b
+		c.SetCookie(&http.Cookie{
+			Name:     name,
+			Value:    strconv.FormatBool(b),
+			Path:     "/",
+			MaxAge:   prefCookieMaxAge,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+	return prefs
+}
+
+// maxConcurrentLogs bounds how many container logs are fetched
+// concurrently for pipelineRun wide log views.
+const maxConcurrentLogs = 8
+
+func PipelineLog(lp logs.Provider) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tc := c.(*tekton.Context)
+		td := &model.TemplateData{}
+		if err := tc.BindTemplateData(td); err != nil {
 			return err
 		}
+		if td.PipelineRun == nil {
+			return c.String(http.StatusNotFound, "pipelineRun not found")
+		}
+
+		var stepLogs []model.StepLogs
+		for _, tr := range td.TaskRuns {
+			if tr == nil {
+				continue
+			}
+			for _, ss := range tr.Status.Steps {
+				stepLogs = append(stepLogs, model.StepLogs{
+					TaskRun: tr,
+					Step:    ss.Name,
+				})
+			}
+		}
+
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, maxConcurrentLogs)
+		for i := range stepLogs {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(sl *model.StepLogs) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				sl.Lines, sl.Err = fetchLines(
+					c.Request().Context(),
+					lp,
+					sl.TaskRun.GetNamespace(),
+					&sl.TaskRun.Status,
+					"step-"+sl.Step,
+				)
+			}(&stepLogs[i])
+		}
+		wg.Wait()
+
+		groupBy := c.QueryParam("group")
+		if groupBy != components.LogGroupByTime {
+			groupBy = components.LogGroupByTask
+		}
 
-		log := bytes.NewBufferString(html.EscapeString(buf.String()))
-		_, err = io.Copy(c.Response(), log)
-		return err
+		c.Response().WriteHeader(http.StatusOK)
+		return components.PipelineLog(td, groupBy, stepLogs).
+			Render(c.Response())
+	}
+}
+
+// fetchLines retrieves the timestamped logs of a container of the
+// taskRun attempt with the given status and splits them into lines.
+func fetchLines(
+	ctx context.Context,
+	lp logs.Provider,
+	namespace string,
+	status *pipelinev1beta1.TaskRunStatus,
+	container string,
+) ([]model.LogLine, error) {
+	opts := &logs.Options{Timestamps: true}
+	if st := status.StartTime; st != nil {
+		opts.Since = st.Time
+	}
+	if ct := status.CompletionTime; ct != nil {
+		opts.Until = ct.Time
+	}
+
+	rc, err := lp.Logs(
+		ctx,
+		logs.ContainerRef{
+			Namespace: namespace,
+			Pod:       status.PodName,
+			Container: container,
+		},
+		opts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var lines []model.LogLine
+	var last time.Time
+	sc := logs.NewScanner(rc)
+	for sc.Scan() {
+		ts, content, ok := logs.SplitTimestamp(sc.Text())
+		// lines without timestamps, e.g. from archives, stick
+		// to the line preceding them
+		if !ok {
+			ts = last
+		}
+		last = ts
+		lines = append(lines, model.LogLine{Time: ts, Content: content})
 	}
+	return lines, sc.Err()
 }
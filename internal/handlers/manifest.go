@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/cezarguimaraes/tkn-dash/internal/components"
 	"github.com/cezarguimaraes/tkn-dash/internal/model"
 	"github.com/cezarguimaraes/tkn-dash/internal/syntax"
 	"github.com/cezarguimaraes/tkn-dash/internal/tekton"
 	"github.com/labstack/echo/v4"
+	g "github.com/maragudk/gomponents"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 )
 
@@ -15,33 +20,114 @@ const (
 	cliLastApply = "kubectl.kubernetes.io/last-applied-configuration"
 )
 
-func Manifest(chromaStyle string) echo.HandlerFunc {
+// Manifest renders the manifest of the browsed taskRun, or of the task
+// spec it resolved to.
+func Manifest(fc *FragmentCache) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		tc := c.(*tekton.Context)
 		td := &model.TemplateData{}
 		if err := tc.BindTemplateData(td); err != nil {
 			return err
 		}
+		if td.TaskRun == nil {
+			return echo.NewHTTPError(http.StatusNotFound, "taskRun not found")
+		}
 
-		// omit commonly large fields
-		tr := td.TaskRun.DeepCopy()
-		tr.ObjectMeta.ManagedFields = nil
-		delete(tr.ObjectMeta.Annotations, cliLastApply)
+		status := td.StatusOf(td.TaskRun)
+		return fc.serve(c, []metav1.Object{td.TaskRun}, td.Step, nil, completedAt(status.CompletionTime), func(buf *bytes.Buffer) (bool, error) {
+			var obj interface{}
+			switch td.Manifest {
+			case model.SpecManifest:
+				if spec := status.TaskSpec; spec != nil {
+					obj = spec
+				}
+			default:
+				tr := td.TaskRun.DeepCopy()
+				omitLargeFields(&tr.ObjectMeta)
+				obj = tr
+			}
 
-		yml, err := yaml.Marshal(tr)
-		if err != nil {
+			manifest, err := formatManifest(td, obj, "manifest-")
+			if err != nil {
+				return false, err
+			}
+			return true, components.StepManifest(td, manifest).Render(buf)
+		})
+	}
+}
+
+// PipelineManifest renders the manifest of the browsed pipelineRun, or
+// of the pipeline spec it resolved to.
+func PipelineManifest(fc *FragmentCache) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tc := c.(*tekton.Context)
+		td := &model.TemplateData{}
+		if err := tc.BindTemplateData(td); err != nil {
 			return err
 		}
+		if td.PipelineRun == nil {
+			return echo.NewHTTPError(http.StatusNotFound, "pipelineRun not found")
+		}
+
+		pr := td.PipelineRun
+		return fc.serve(c, []metav1.Object{pr}, "", nil, completedAt(pr.Status.CompletionTime), func(buf *bytes.Buffer) (bool, error) {
+			var obj interface{}
+			switch td.Manifest {
+			case model.SpecManifest:
+				if spec := pr.Status.PipelineSpec; spec != nil {
+					obj = spec
+				}
+			default:
+				pr := pr.DeepCopy()
+				omitLargeFields(&pr.ObjectMeta)
+				obj = pr
+			}
+
+			manifest, err := formatManifest(td, obj, "pipeline-manifest-")
+			if err != nil {
+				return false, err
+			}
+			return true, components.PipelineRunManifest(td, manifest).Render(buf)
+		})
+	}
+}
+
+// omitLargeFields drops commonly large fields of little interest.
+func omitLargeFields(meta *metav1.ObjectMeta) {
+	meta.ManagedFields = nil
+	delete(meta.Annotations, cliLastApply)
+}
+
+// formatManifest marshals obj in the format of td and highlights it.
+// A nil obj stands for a spec which was not resolved yet.
+func formatManifest(td *model.TemplateData, obj interface{}, linkPrefix string) (g.Node, error) {
+	if obj == nil {
+		return g.Text("spec not resolved yet"), nil
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+	switch td.Format {
+	case model.JSONFormat:
+		b, err = json.MarshalIndent(obj, "", "  ")
+	default:
+		b, err = yaml.Marshal(obj)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		c.Response().WriteHeader(http.StatusOK)
-		components.StepDetailsTabs(td, "manifest", true).
-			Render(c.Response().Writer)
-		return syntax.FormatHTML(
-			c.Response().Writer,
-			string(yml),
-			syntax.WithStyle(chromaStyle),
-			syntax.WithLinkPrefix("manifest-"),
-			syntax.WithFallbackLanguage("yaml"),
-		)
+	var sb strings.Builder
+	err = syntax.FormatHTML(
+		&sb,
+		string(b),
+		syntax.WithLinkPrefix(linkPrefix),
+		syntax.WithLanguage(td.Format),
+	)
+	if err != nil {
+		return nil, err
 	}
+	return g.Raw(sb.String()), nil
 }
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/syntax"
+	"github.com/labstack/echo/v4"
+)
+
+// SyntaxStylesheet serves the stylesheet of a chroma style, styling
+// the classes of highlighted code.
+func SyntaxStylesheet() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		style := c.Param("style")
+		if !syntax.HasStyle(style) {
+			return echo.NewHTTPError(http.StatusNotFound, "unknown syntax style")
+		}
+		var buf bytes.Buffer
+		if err := syntax.WriteCSS(&buf, style); err != nil {
+			return err
+		}
+		c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=86400")
+		return c.Blob(http.StatusOK, "text/css; charset=utf-8", buf.Bytes())
+	}
+}
+
+// Preferences persists the syntax style and theme sent as query params
+// in the user's cookies, and refreshes the page to apply them.
+func Preferences() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		set := func(name, value string) {
+			c.SetCookie(&http.Cookie{
+				Name:     name,
+				Value:    value,
+				Path:     "/",
+				MaxAge:   prefCookieMaxAge,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+		if style := c.QueryParam(model.SyntaxStyleCookie); style != "" {
+			if !syntax.HasStyle(style) {
+				return c.String(http.StatusBadRequest, "unknown syntax style")
+			}
+			set(model.SyntaxStyleCookie, style)
+		}
+		switch theme := c.QueryParam(model.ThemeCookie); theme {
+		case "":
+		case model.LightTheme, model.DarkTheme:
+			set(model.ThemeCookie, theme)
+		default:
+			return c.String(http.StatusBadRequest, "unknown theme")
+		}
+		c.Response().Header().Set("HX-Refresh", "true")
+		return c.NoContent(http.StatusOK)
+	}
+}
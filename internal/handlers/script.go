@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"bytes"
 	"net/http"
 
 	"github.com/cezarguimaraes/tkn-dash/internal/components"
@@ -8,10 +9,13 @@ import (
 	"github.com/cezarguimaraes/tkn-dash/internal/syntax"
 	"github.com/cezarguimaraes/tkn-dash/internal/tekton"
 	"github.com/labstack/echo/v4"
-	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 )
 
-func StepScript(chromaStyle string) echo.HandlerFunc {
+// languageAnnotation overrides the detected language of the scripts of
+// a taskRun, or of a single step when suffixed with "." and its name.
+const languageAnnotation = "tkn-dash/language"
+
+func StepScript(fc *FragmentCache) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		tc := c.(*tekton.Context)
 		td := &model.TemplateData{}
@@ -19,22 +23,63 @@ func StepScript(chromaStyle string) echo.HandlerFunc {
 			return err
 		}
 
-		var foundStep pipelinev1beta1.Step
-		for _, step := range td.TaskRun.Status.TaskSpec.Steps {
-			if step.Name == td.Step {
-				foundStep = step
+		if td.TaskRun == nil {
+			return echo.NewHTTPError(http.StatusNotFound, "taskRun not found")
+		}
+
+		// init containers are created by tekton and have no script.
+		// Resolved scripts are substituted from the raw ones.
+		var script, image string
+		if spec := td.TaskSpecOf(td.TaskRun); spec != nil {
+			switch td.ContainerKind {
+			case model.StepContainer:
+				for _, step := range spec.Steps {
+					if step.Name == td.Step {
+						script, image = step.Script, step.Image
+					}
+				}
+			case model.SidecarContainer:
+				for _, sc := range spec.Sidecars {
+					if sc.Name == td.Step {
+						script, image = sc.Script, sc.Image
+					}
+				}
 			}
 		}
 
-		c.Response().WriteHeader(http.StatusOK)
-		components.StepDetailsTabs(td, "script", true).
-			Render(c.Response().Writer)
+		objs, modified := scriptSources(td)
+		return fc.serve(c, objs, td.Step, nil, modified, func(buf *bytes.Buffer) (bool, error) {
+			opts := []syntax.Option{
+				syntax.WithLinkPrefix("script-"),
+				syntax.WithImage(image),
+				syntax.WithReferences(
+					components.SubstitutionPattern,
+					components.Substitutions(td),
+				),
+			}
+			// the step annotation takes precedence over the taskRun one,
+			// and the query over both
+			annotations := td.TaskRun.GetAnnotations()
+			for _, lang := range []string{
+				annotations[languageAnnotation],
+				annotations[languageAnnotation+"."+td.Step],
+			} {
+				if lang != "" {
+					opts = append(opts, syntax.WithLanguage(lang))
+				}
+			}
+			auto := syntax.Language(script, opts...)
+			if td.Language != "" {
+				opts = append(opts, syntax.WithLanguage(td.Language))
+			}
 
-		return syntax.FormatHTML(
-			c.Response().Writer,
-			foundStep.Script,
-			syntax.WithStyle(chromaStyle),
-			syntax.WithLinkPrefix("script-"),
-		)
+			if err := components.StepDetailsTabs(td, "script", true).Render(buf); err != nil {
+				return false, err
+			}
+			if err := components.ScriptToolbar(td, auto).Render(buf); err != nil {
+				return false, err
+			}
+			return true, syntax.FormatHTML(buf, script, opts...)
+		})
 	}
 }
@@ -6,10 +6,12 @@ import (
 	"time"
 
 	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/status"
 	"github.com/cezarguimaraes/tkn-dash/internal/tekton"
 	"github.com/cezarguimaraes/tkn-dash/pkg/cache"
 	"github.com/labstack/echo/v4"
 	"github.com/maragudk/gomponents"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"knative.dev/pkg/apis"
@@ -75,21 +77,18 @@ func Search(r renderer) echo.HandlerFunc {
 					"?" + qs.Encode()
 			}
 
-			var status string
-			if st, ok := r.(statusConditionAccessor); ok {
-				cond := st.GetStatusCondition().
-					GetCondition(apis.ConditionSucceeded)
-
-				if cond != nil {
-					if cond.IsUnknown() {
-						status = "Running"
-					}
-					if cond.IsFalse() {
-						status = "Failed"
-					}
-					if cond.IsTrue() {
-						status = "Succeeded"
-					}
+			var st status.Status
+			var reason string
+			switch run := r.(type) {
+			case *pipelinev1beta1.PipelineRun:
+				st = status.OfPipelineRun(run)
+			case *pipelinev1beta1.TaskRun:
+				st = status.OfTaskRun(&run.Status)
+			}
+			if acc, ok := r.(statusConditionAccessor); ok {
+				if cond := acc.GetStatusCondition().
+					GetCondition(apis.ConditionSucceeded); cond != nil {
+					reason = cond.Reason
 				}
 			}
 
@@ -98,7 +97,8 @@ func Search(r renderer) echo.HandlerFunc {
 				Namespace: obj.GetNamespace(),
 				Name:      obj.GetName(),
 				NextPage:  nextPage,
-				Status:    status,
+				Status:    st,
+				Reason:    reason,
 				Age: ageString(
 					now.Sub(obj.GetCreationTimestamp().Time),
 				) + " ago",
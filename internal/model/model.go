@@ -1,8 +1,47 @@
 package model
 
 import (
+	"text/template"
+	"time"
+
+	"github.com/cezarguimaraes/tkn-dash/internal/status"
 	"github.com/maragudk/gomponents"
 	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RunManifest and SpecManifest are the documents manifest views
+	// render: the run object itself, or the spec it resolved to.
+	RunManifest  = "run"
+	SpecManifest = "spec"
+
+	YAMLFormat = "yaml"
+	JSONFormat = "json"
+
+	// RawScript and ResolvedScript render the variables tekton
+	// substitutes in step scripts as written, or as their values.
+	RawScript      = "raw"
+	ResolvedScript = "resolved"
+)
+
+const (
+	// LightTheme and DarkTheme are the daisyUI themes users switch
+	// between.
+	LightTheme = "light"
+	DarkTheme  = "night"
+
+	// SyntaxStyleCookie and ThemeCookie persist the syntax style and
+	// theme users picked.
+	SyntaxStyleCookie = "syntax-style"
+	ThemeCookie       = "theme"
+)
+
+const (
+	StepContainer    = "step"
+	SidecarContainer = "sidecar"
+	InitContainer    = "init"
 )
 
 type TektonComponent func(*TemplateData) gomponents.Node
@@ -23,26 +62,138 @@ type TemplateData struct {
 	// TaskRun is resolved from the :taskRun url param
 	TaskRun *pipelinev1beta1.TaskRun
 
+	// ParentPipelineRun is the pipelineRun owning TaskRun, resolved
+	// when browsing it outside of its pipelineRun.
+	ParentPipelineRun *pipelinev1beta1.PipelineRun
+
+	// Ancestry lists the pipelineRuns the browsed pipelineRun, or the
+	// parent of the browsed taskRun, is nested in, outermost first.
+	Ancestry []*pipelinev1beta1.PipelineRun
+
+	// ChildPipelineRuns are the pipelineRuns started by PipelineRun.
+	ChildPipelineRuns []*pipelinev1beta1.PipelineRun
+
 	// TaskRuns is the list of taskRuns that should be rendered
 	// in the middle "step view". It is either a list containing
 	// a single taskRun in taskRun view, or the list of taskRuns
 	// pertaining to a pipelineRUn
 	TaskRuns []*pipelinev1beta1.TaskRun
 
+	// Left and Right are the runs being compared, resolved from the
+	// :left and :right url params.
+	Left, Right *Run
+
 	// Step is the name of the step resolved from the :step url param
 	Step string
 
+	// ContainerKind is the kind of container Step refers to, resolved
+	// from the ?container query param: a step, sidecar or init container.
+	ContainerKind string
+
 	Tab string
 
+	// View is the pipelineRun wide view resolved from the :view url param
+	View string
+
+	// Manifest is the document manifest views render, resolved from
+	// the ?manifest query param. It defaults to RunManifest.
+	Manifest string
+
+	// Format is the format manifest views render in, resolved from
+	// the ?format query param. It defaults to YAMLFormat.
+	Format string
+
+	// Script is how the script view renders the variables tekton
+	// substitutes, resolved from the ?script query param. It defaults
+	// to RawScript.
+	Script string
+
+	// Language overrides the detected language of scripts, resolved
+	// from the ?language query param.
+	Language string
+
+	// Attempt is the 1-indexed attempt of TaskRun resolved from the
+	// ?attempt query param. Zero selects its latest attempt.
+	Attempt int
+
+	// Pod is the pod running the browsed attempt of TaskRun. It is only
+	// resolved when running against a cluster and while the pod exists.
+	Pod *corev1.Pod
+
+	// SyntaxStyle is the chroma style used to highlight code snippets,
+	// resolved from the syntax-style cookie.
+	SyntaxStyle string
+
+	// Theme is the daisyUI theme of the page, resolved from the theme
+	// cookie.
+	Theme string
+
+	// GitWebURL is the template of the web URL of git sources, executed
+	// against a GitSource. Nil disables links to git sources.
+	GitWebURL *template.Template
+
 	URLFor func(name string, args ...interface{}) string
 }
 
+// Run is a pipelineRun along with its taskRuns, or a lone taskRun.
+type Run struct {
+	// PipelineRun is nil for taskRuns.
+	PipelineRun *pipelinev1beta1.PipelineRun
+	TaskRuns    []*pipelinev1beta1.TaskRun
+}
+
+// Object returns the pipelineRun, or the taskRun.
+func (r *Run) Object() metav1.Object {
+	if r.PipelineRun != nil {
+		return r.PipelineRun
+	}
+	return r.TaskRuns[0]
+}
+
+// StatusOf returns the status of the attempt of tr being browsed.
+// Retries of taskRuns other than TaskRun are never browsed, so their
+// latest status is returned.
+func (td *TemplateData) StatusOf(tr *pipelinev1beta1.TaskRun) *pipelinev1beta1.TaskRunStatus {
+	if td.TaskRun == nil || tr.GetName() != td.TaskRun.GetName() {
+		return &tr.Status
+	}
+	if retries := tr.Status.RetriesStatus; td.Attempt > 0 && td.Attempt <= len(retries) {
+		return &retries[td.Attempt-1]
+	}
+	return &tr.Status
+}
+
+// TaskSpecOf returns the task spec the browsed attempt of tr ran, as
+// written before tekton substituted its variables: the spec embedded in
+// tr or, for referenced tasks, the spec tekton recorded in its status
+// when resolving the reference, as it only substitutes variables when
+// creating pods. It is nil until the reference is resolved.
+func (td *TemplateData) TaskSpecOf(tr *pipelinev1beta1.TaskRun) *pipelinev1beta1.TaskSpec {
+	if spec := tr.Spec.TaskSpec; spec != nil {
+		return spec
+	}
+	return td.StatusOf(tr).TaskSpec
+}
+
+// ContainerName returns the name of the pod container running Step.
+func (td *TemplateData) ContainerName() string {
+	switch td.ContainerKind {
+	case SidecarContainer:
+		return "sidecar-" + td.Step
+	case InitContainer:
+		return td.Step
+	}
+	return "step-" + td.Step
+}
+
 type SearchItem struct {
 	Namespace string
 	Name      string
 	Age       string
-	Status    string
-	NextPage  string
+	Status    status.Status
+	// Reason is the reason of the Succeeded condition, if any
+	Reason   string
+	NextPage string
 }
 
 type SearchResults struct {
@@ -50,3 +201,38 @@ type SearchResults struct {
 	Items    []SearchItem
 	URLFor   func(string, ...interface{}) string
 }
+
+// GitSource is a file of a git repository at a given revision.
+type GitSource struct {
+	Repo     string
+	Revision string
+	Path     string
+}
+
+// LogLine is a single line of a container log, with its timestamp
+// already parsed out of it.
+type LogLine struct {
+	Time    time.Time
+	Content string
+}
+
+// StepLogs holds the logs of a single step of a taskRun.
+type StepLogs struct {
+	TaskRun *pipelinev1beta1.TaskRun
+	Step    string
+	Lines   []LogLine
+	Err     error
+}
+
+// LogPreferences are the user's log viewer settings, persisted
+// across requests.
+type LogPreferences struct {
+	// Timestamps shows the time each line was written at.
+	Timestamps bool
+
+	// Relative shows each line's offset from the start of its step.
+	Relative bool
+
+	// Wrap wraps long lines instead of scrolling them horizontally.
+	Wrap bool
+}
@@ -0,0 +1,203 @@
+// Package status derives a single status out of the conditions and
+// container states of pipelineRuns, taskRuns and their steps.
+package status
+
+import (
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+// Status is the state a run or step is in.
+type Status string
+
+const (
+	// Unknown is the status of runs without a Succeeded condition.
+	Unknown          Status = ""
+	Pending          Status = "Pending"
+	Running          Status = "Running"
+	Succeeded        Status = "Succeeded"
+	Failed           Status = "Failed"
+	Skipped          Status = "Skipped"
+	Cancelled        Status = "Cancelled"
+	TimedOut         Status = "TimedOut"
+	ContinuedOnError Status = "ContinuedOnError"
+)
+
+// Label returns the human readable name of s.
+func (s Status) Label() string {
+	switch s {
+	case Unknown:
+		return "Unknown"
+	case TimedOut:
+		return "Timed out"
+	case ContinuedOnError:
+		return "Continued on error"
+	}
+	return string(s)
+}
+
+// Done reports whether s is final.
+func (s Status) Done() bool {
+	switch s {
+	case Unknown, Pending, Running:
+		return false
+	}
+	return true
+}
+
+// pendingReasons are the reasons of Unknown conditions set while a run
+// is still waiting for its refs to be resolved or for its pod to be
+// scheduled. Pod reasons are those of tekton's pkg/pod.
+var pendingReasons = map[string]bool{
+	string(pipelinev1beta1.PipelineRunReasonPending): true,
+	pipelinev1beta1.TaskRunReasonResolvingTaskRef:    true,
+	"ResolvingPipelineRef":                           true,
+	// TaskRunPending is set on pending taskRuns by newer tekton versions
+	"TaskRunPending":        true,
+	"Pending":               true,
+	"ExceededResourceQuota": true,
+	"ExceededNodeResources": true,
+	"PodCreationFailed":     true,
+}
+
+var cancelledReasons = map[string]bool{
+	string(pipelinev1beta1.PipelineRunReasonCancelled):               true,
+	string(pipelinev1beta1.PipelineRunReasonCancelledRunningFinally): true,
+	string(pipelinev1beta1.PipelineRunReasonStoppedRunningFinally):   true,
+	string(pipelinev1beta1.TaskRunReasonCancelled):                   true,
+	// PipelineRunCancelled is the reason of deprecated cancellations
+	"PipelineRunCancelled": true,
+}
+
+var timedOutReasons = map[string]bool{
+	string(pipelinev1beta1.PipelineRunReasonTimedOut): true,
+	string(pipelinev1beta1.TaskRunReasonTimedOut):     true,
+}
+
+// FromCondition derives the status of a run from its Succeeded
+// condition.
+func FromCondition(cond *apis.Condition) Status {
+	if cond == nil {
+		return Unknown
+	}
+	switch {
+	case cond.IsTrue():
+		return Succeeded
+	case cond.IsFalse():
+		switch {
+		case cancelledReasons[cond.Reason]:
+			return Cancelled
+		case timedOutReasons[cond.Reason]:
+			return TimedOut
+		}
+		return Failed
+	}
+	if pendingReasons[cond.Reason] {
+		return Pending
+	}
+	return Running
+}
+
+// OfPipelineRun returns the status of pr.
+func OfPipelineRun(pr *pipelinev1beta1.PipelineRun) Status {
+	if pr.IsPending() {
+		return Pending
+	}
+	s := FromCondition(pr.Status.GetCondition(apis.ConditionSucceeded))
+	return unconditioned(s, pr.Status.StartTime != nil)
+}
+
+// OfTaskRun returns the status of a taskRun attempt.
+func OfTaskRun(st *pipelinev1beta1.TaskRunStatus) Status {
+	s := FromCondition(st.GetCondition(apis.ConditionSucceeded))
+	return unconditioned(s, st.StartTime != nil)
+}
+
+// unconditioned tells runs without a condition yet apart by whether
+// they started.
+func unconditioned(s Status, started bool) Status {
+	switch {
+	case s != Unknown:
+		return s
+	case started:
+		return Running
+	}
+	return Pending
+}
+
+// OfSteps returns the status of each step of a taskRun attempt, in
+// order. Steps following a failed one are skipped by tekton, which
+// terminates them with a non-zero exit code, unless their onError is
+// continue.
+func OfSteps(st *pipelinev1beta1.TaskRunStatus) []Status {
+	onError := map[string]pipelinev1beta1.OnErrorType{}
+	if spec := st.TaskSpec; spec != nil {
+		for _, s := range spec.Steps {
+			onError[s.Name] = s.OnError
+		}
+	}
+	statuses := make([]Status, len(st.Steps))
+	failed := false
+	for i, ss := range st.Steps {
+		s := OfContainer(ss.ContainerState, onError[ss.Name])
+		if s == Failed {
+			if failed {
+				s = Skipped
+			}
+			failed = true
+		}
+		statuses[i] = s
+	}
+	return statuses
+}
+
+// OfContainer returns the status of a step, sidecar or init container.
+// onError is the onError behavior of steps, if any.
+func OfContainer(state corev1.ContainerState, onError pipelinev1beta1.OnErrorType) Status {
+	t := state.Terminated
+	switch {
+	case t != nil && t.ExitCode == 0:
+		return Succeeded
+	case t != nil && cancelledReasons[t.Reason]:
+		return Cancelled
+	case t != nil && timedOutReasons[t.Reason]:
+		return TimedOut
+	case t != nil && onError == pipelinev1beta1.Continue:
+		return ContinuedOnError
+	case t != nil:
+		return Failed
+	case state.Running != nil:
+		return Running
+	}
+	return Pending
+}
+
+// Aggregate returns the status of a group of runs, such as the
+// taskRuns a matrixed pipelineTask fans out to. The group is in progress
+// while any of its runs is, and otherwise takes the worst status among
+// them.
+func Aggregate(statuses ...Status) Status {
+	if len(statuses) == 0 {
+		return Unknown
+	}
+	count := map[Status]int{}
+	for _, s := range statuses {
+		count[s]++
+	}
+	if inProgress := count[Unknown] + count[Pending] + count[Running]; inProgress > 0 {
+		if count[Pending] == len(statuses) {
+			return Pending
+		}
+		return Running
+	}
+	for _, s := range []Status{Failed, TimedOut, Cancelled, ContinuedOnError} {
+		if count[s] > 0 {
+			return s
+		}
+	}
+	if count[Skipped] == len(statuses) {
+		return Skipped
+	}
+	return Succeeded
+}
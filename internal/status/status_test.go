@@ -0,0 +1,180 @@
+package status
+
+import (
+	"testing"
+
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func succeeded(status corev1.ConditionStatus, reason string) duckv1.Status {
+	return duckv1.Status{Conditions: duckv1.Conditions{{
+		Type:   apis.ConditionSucceeded,
+		Status: status,
+		Reason: reason,
+	}}}
+}
+
+func TestOfTaskRun(t *testing.T) {
+	started := &metav1.Time{}
+	tests := []struct {
+		name   string
+		status pipelinev1beta1.TaskRunStatus
+		want   Status
+	}{
+		{"not started", pipelinev1beta1.TaskRunStatus{}, Pending},
+		{"resolving", pipelinev1beta1.TaskRunStatus{
+			Status: succeeded(corev1.ConditionUnknown, pipelinev1beta1.TaskRunReasonResolvingTaskRef),
+		}, Pending},
+		{"pod pending", pipelinev1beta1.TaskRunStatus{
+			Status: succeeded(corev1.ConditionUnknown, "Pending"),
+		}, Pending},
+		{"started without condition", pipelinev1beta1.TaskRunStatus{
+			TaskRunStatusFields: pipelinev1beta1.TaskRunStatusFields{
+				StartTime: started,
+			},
+		}, Running},
+		{"running", pipelinev1beta1.TaskRunStatus{
+			Status: succeeded(corev1.ConditionUnknown, "Running"),
+			TaskRunStatusFields: pipelinev1beta1.TaskRunStatusFields{
+				StartTime: started,
+			},
+		}, Running},
+		{"succeeded", pipelinev1beta1.TaskRunStatus{
+			Status: succeeded(corev1.ConditionTrue, "Succeeded"),
+		}, Succeeded},
+		{"failed", pipelinev1beta1.TaskRunStatus{
+			Status: succeeded(corev1.ConditionFalse, "Failed"),
+		}, Failed},
+		{"cancelled", pipelinev1beta1.TaskRunStatus{
+			Status: succeeded(corev1.ConditionFalse, pipelinev1beta1.TaskRunReasonCancelled.String()),
+		}, Cancelled},
+		{"timed out", pipelinev1beta1.TaskRunStatus{
+			Status: succeeded(corev1.ConditionFalse, pipelinev1beta1.TaskRunReasonTimedOut.String()),
+		}, TimedOut},
+	}
+	for _, tt := range tests {
+		if got := OfTaskRun(&tt.status); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOfPipelineRun(t *testing.T) {
+	pending := &pipelinev1beta1.PipelineRun{
+		Spec: pipelinev1beta1.PipelineRunSpec{
+			Status: pipelinev1beta1.PipelineRunSpecStatusPending,
+		},
+	}
+	if got := OfPipelineRun(pending); got != Pending {
+		t.Errorf("spec pending: got %q, want %q", got, Pending)
+	}
+
+	cancelled := &pipelinev1beta1.PipelineRun{}
+	cancelled.Status.Status = succeeded(
+		corev1.ConditionFalse,
+		pipelinev1beta1.PipelineRunReasonCancelledRunningFinally.String(),
+	)
+	if got := OfPipelineRun(cancelled); got != Cancelled {
+		t.Errorf("cancelled: got %q, want %q", got, Cancelled)
+	}
+}
+
+func TestOfContainer(t *testing.T) {
+	terminated := func(code int32, reason string) corev1.ContainerState {
+		return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: code,
+			Reason:   reason,
+		}}
+	}
+	tests := []struct {
+		name    string
+		state   corev1.ContainerState
+		onError pipelinev1beta1.OnErrorType
+		want    Status
+	}{
+		{"not started", corev1.ContainerState{}, "", Pending},
+		{"waiting", corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"},
+		}, "", Pending},
+		{"running", corev1.ContainerState{
+			Running: &corev1.ContainerStateRunning{},
+		}, "", Running},
+		{"succeeded", terminated(0, "Completed"), "", Succeeded},
+		{"failed", terminated(1, "Error"), "", Failed},
+		{"oom killed", terminated(137, "OOMKilled"), "", Failed},
+		{"continued", terminated(1, "Error"), pipelinev1beta1.Continue, ContinuedOnError},
+		{"cancelled", terminated(1, "TaskRunCancelled"), "", Cancelled},
+		{"timed out", terminated(1, "TaskRunTimeout"), pipelinev1beta1.Continue, TimedOut},
+	}
+	for _, tt := range tests {
+		if got := OfContainer(tt.state, tt.onError); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOfSteps(t *testing.T) {
+	step := func(name string, code int32) pipelinev1beta1.StepState {
+		return pipelinev1beta1.StepState{
+			Name: name,
+			ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: code,
+			}},
+		}
+	}
+	st := &pipelinev1beta1.TaskRunStatus{
+		TaskRunStatusFields: pipelinev1beta1.TaskRunStatusFields{
+			Steps: []pipelinev1beta1.StepState{
+				step("build", 0),
+				step("test", 1),
+				step("lint", 1),
+				step("report", 1),
+				step("publish", 1),
+			},
+			TaskSpec: &pipelinev1beta1.TaskSpec{
+				Steps: []pipelinev1beta1.Step{
+					{Name: "build"},
+					{Name: "test"},
+					{Name: "lint"},
+					{Name: "report", OnError: pipelinev1beta1.Continue},
+					{Name: "publish"},
+				},
+			},
+		},
+	}
+	want := []Status{Succeeded, Failed, Skipped, ContinuedOnError, Skipped}
+	got := OfSteps(st)
+	if len(got) != len(want) {
+		t.Fatalf("got %d statuses, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("step %s: got %q, want %q", st.Steps[i].Name, got[i], want[i])
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		statuses []Status
+		want     Status
+	}{
+		{nil, Unknown},
+		{[]Status{Pending, Pending}, Pending},
+		{[]Status{Succeeded, Pending}, Running},
+		{[]Status{Failed, Running}, Running},
+		{[]Status{Succeeded, Failed, Cancelled}, Failed},
+		{[]Status{Succeeded, TimedOut}, TimedOut},
+		{[]Status{Succeeded, Skipped}, Succeeded},
+		{[]Status{Skipped, Skipped}, Skipped},
+	}
+	for _, tt := range tests {
+		if got := Aggregate(tt.statuses...); got != tt.want {
+			t.Errorf("Aggregate(%v) = %q, want %q", tt.statuses, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package syntax
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+// WithImage hints the language of scripts from the name of the image
+// running them, when their shebang does not tell it.
+func WithImage(image string) Option {
+	return func(opt *options) {
+		opt.image = image
+	}
+}
+
+// interpreters maps interpreters, stripped of their version, to the
+// lexer of their language.
+var interpreters = map[string]string{
+	"sh":         "bash",
+	"ash":        "bash",
+	"bash":       "bash",
+	"dash":       "bash",
+	"ksh":        "bash",
+	"zsh":        "bash",
+	"python":     "python",
+	"node":       "javascript",
+	"nodejs":     "javascript",
+	"deno":       "typescript",
+	"ruby":       "ruby",
+	"perl":       "perl",
+	"php":        "php",
+	"pwsh":       "powershell",
+	"powershell": "powershell",
+	"groovy":     "groovy",
+}
+
+// images maps substrings of image names to the lexer of the language
+// they are built for.
+var images = []struct {
+	substring, language string
+}{
+	{"python", "python"},
+	{"node", "javascript"},
+	{"golang", "go"},
+	{"ruby", "ruby"},
+	{"powershell", "powershell"},
+}
+
+// Language returns the name of the lexer FormatHTML highlights script
+// with, given the same options.
+func Language(script string, opts ...Option) string {
+	opt := &options{}
+	for _, o := range opts {
+		o(opt)
+	}
+	return detectLexer(script, opt).Config().Name
+}
+
+// Languages lists the names of all lexers, sorted.
+func Languages() []string {
+	names := lexers.Names(false)
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names
+}
+
+// detectLexer picks the lexer of script: the explicit language if
+// any, then the one told by its shebang, its image, chroma's analysers
+// and finally the fallback language.
+func detectLexer(script string, opt *options) chroma.Lexer {
+	for _, lang := range []string{
+		deref(opt.language),
+		shebangLanguage(script),
+		imageLanguage(opt.image),
+	} {
+		if lang == "" {
+			continue
+		}
+		if l := lexers.Get(lang); l != nil {
+			return l
+		}
+	}
+	if l := lexers.Analyse(script); l != nil {
+		return l
+	}
+	if l := lexers.Get(deref(opt.fallback)); l != nil {
+		return l
+	}
+	return lexers.Fallback
+}
+
+// shebangLanguage returns the language of the interpreter named by the
+// shebang of script, as in #!/bin/bash or #!/usr/bin/env python3.
+func shebangLanguage(script string) string {
+	line, _, _ := strings.Cut(script, "\n")
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+	args := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(args) == 0 {
+		return ""
+	}
+	interpreter := path.Base(args[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, arg := range args[1:] {
+			// skip flags such as -S and variables assignments
+			if !strings.HasPrefix(arg, "-") && !strings.Contains(arg, "=") {
+				interpreter = path.Base(arg)
+				break
+			}
+		}
+	}
+	// python3.11 is python
+	interpreter = strings.TrimRight(interpreter, "0123456789.")
+	if lang, ok := interpreters[interpreter]; ok {
+		return lang
+	}
+	return interpreter
+}
+
+// imageLanguage returns the language image is built for, judging by the
+// name of its repository.
+func imageLanguage(image string) string {
+	if image == "" {
+		return ""
+	}
+	image, _, _ = strings.Cut(image, "@")
+	repo := path.Base(image)
+	repo, _, _ = strings.Cut(repo, ":")
+	for _, img := range images {
+		if strings.Contains(repo, img.substring) {
+			return img.language
+		}
+	}
+	return ""
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -1,17 +1,22 @@
 package syntax
 
 import (
+	"bytes"
+	"html/template"
 	"io"
+	"regexp"
+	"strconv"
 
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/formatters/html"
-	"github.com/alecthomas/chroma/v2/lexers"
 	"github.com/alecthomas/chroma/v2/styles"
 )
 
-func WithStyle(style string) Option {
+// WithLanguage skips language detection, highlighting
+// scripts as the given language.
+func WithLanguage(lang string) Option {
 	return func(opt *options) {
-		opt.style = &style
+		opt.language = &lang
 	}
 }
 
@@ -27,55 +32,169 @@ func WithLinkPrefix(prefix string) Option {
 	}
 }
 
-var (
-	defaultStyle      = "github-dark"
-	defaultLinkPrefix = "script"
-)
-
-func FormatHTML(w io.Writer, script string, opts ...Option) error {
-	opt := &options{
-		style:  &defaultStyle,
-		prefix: &defaultLinkPrefix,
-	}
-	for _, o := range opts {
-		o(opt)
+func WithLineNumbers(enabled bool) Option {
+	return func(opt *options) {
+		opt.lineNumbers = enabled
 	}
+}
 
-	lexer := lexers.Analyse(script)
-	if lexer == nil {
-		if opt.fallback != nil {
-			lexer = lexers.Get(*opt.fallback)
-		}
-		if lexer == nil {
-			lexer = lexers.Fallback
-		}
+// Reference is a substring of a script standing for a value, such as
+// the variables tekton substitutes in step scripts.
+type Reference struct {
+	// Text is rendered in place of the reference.
+	Text string
+	// Title describes the reference when hovering over it.
+	Title string
+	// Href, when set, links the reference.
+	Href string
+}
+
+// WithReferences highlights the substrings of the script matched by
+// pattern, rendering them as resolved by resolve.
+func WithReferences(pattern *regexp.Regexp, resolve func(string) Reference) Option {
+	return func(opt *options) {
+		opt.references = pattern
+		opt.resolve = resolve
 	}
-	lexer = chroma.Coalesce(lexer)
+}
 
-	style := styles.Get(*opt.style)
-	formatter := html.New(
+var defaultLinkPrefix = "script"
+
+// classPrefix prefixes the classes of highlighted tokens, keeping them
+// apart from the ones of the page.
+const classPrefix = "hl-"
+
+func newFormatter(lineNumbers bool, linkPrefix string) *html.Formatter {
+	return html.New(
 		html.BaseLineNumber(0),
-		html.WithLineNumbers(true),
-		html.WithClasses(false),
+		html.WithLineNumbers(lineNumbers),
+		html.WithClasses(true),
+		html.ClassPrefix(classPrefix),
 		html.LineNumbersInTable(true),
-		html.WithLinkableLineNumbers(true, *opt.prefix),
+		html.WithLinkableLineNumbers(true, linkPrefix),
 		html.TabWidth(4),
 		html.WithAllClasses(true),
 		html.WrapLongLines(true),
 	)
+}
+
+// FormatHTML highlights script, styling tokens through classes defined
+// by the stylesheet WriteCSS writes.
+func FormatHTML(w io.Writer, script string, opts ...Option) error {
+	opt := &options{
+		prefix:      &defaultLinkPrefix,
+		lineNumbers: true,
+	}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	lexer := chroma.Coalesce(detectLexer(script, opt))
+
+	// classes are the same for every style
+	style := styles.Fallback
+	formatter := newFormatter(opt.lineNumbers, *opt.prefix)
 
 	iterator, err := lexer.Tokenise(nil, script)
 	if err != nil {
 		return err
 	}
 
-	return formatter.Format(w, style, iterator)
+	if opt.references == nil {
+		return formatter.Format(w, style, iterator)
+	}
+
+	// references are matched against the whole script, as lexers may
+	// split them across tokens, and replaced by a token holding a
+	// placeholder swapped for the resolved reference once formatted
+	locs := opt.references.FindAllStringIndex(script, -1)
+	var refs []Reference
+	var tokens []chroma.Token
+	offset, next := 0, 0
+	for t := iterator(); t != chroma.EOF; t = iterator() {
+		start, end := offset, offset+len(t.Value)
+		offset = end
+		for pos := start; pos < end; {
+			switch {
+			case next < len(locs) && pos >= locs[next][1]:
+				next++
+			case next < len(locs) && pos >= locs[next][0]:
+				loc := locs[next]
+				if pos == loc[0] {
+					tokens = append(tokens, chroma.Token{
+						Type:  chroma.NameVariable,
+						Value: placeholder(len(refs)),
+					})
+					refs = append(refs, opt.resolve(script[loc[0]:loc[1]]))
+				}
+				pos = loc[1]
+				if pos > end {
+					pos = end
+				}
+			default:
+				stop := end
+				if next < len(locs) && locs[next][0] < stop {
+					stop = locs[next][0]
+				}
+				tokens = append(tokens, chroma.Token{Type: t.Type, Value: t.Value[pos-start : stop-start]})
+				pos = stop
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, style, chroma.Literator(tokens...)); err != nil {
+		return err
+	}
+	out := buf.Bytes()
+	for i, ref := range refs {
+		out = bytes.Replace(out, []byte(placeholder(i)), []byte(referenceHTML(ref)), 1)
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// WriteCSS writes the stylesheet of the given chroma style, falling
+// back to the default style for unknown ones.
+func WriteCSS(w io.Writer, style string) error {
+	return newFormatter(true, defaultLinkPrefix).WriteCSS(w, styles.Get(style))
+}
+
+// HasStyle tells whether style names a chroma style.
+func HasStyle(style string) bool {
+	_, ok := styles.Registry[style]
+	return ok
+}
+
+// Styles lists the names of all chroma styles, sorted.
+func Styles() []string {
+	return styles.Names()
+}
+
+func placeholder(i int) string {
+	return "\x00ref" + strconv.Itoa(i) + "\x00"
+}
+
+func referenceHTML(ref Reference) string {
+	tag, href := "span", ""
+	if ref.Href != "" {
+		tag, href = "a", ` href="`+template.HTMLEscapeString(ref.Href)+`"`
+	}
+	return "<" + tag + href +
+		` title="` + template.HTMLEscapeString(ref.Title) + `"` +
+		` style="text-decoration: underline dotted; cursor: help;">` +
+		template.HTMLEscapeString(ref.Text) +
+		"</" + tag + ">"
 }
 
 type options struct {
-	fallback *string
-	style    *string
-	prefix   *string
+	language    *string
+	fallback    *string
+	image       string
+	prefix      *string
+	lineNumbers bool
+	references  *regexp.Regexp
+	resolve     func(string) Reference
 }
 
 type Option func(*options)
@@ -0,0 +1,63 @@
+package syntax
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFormatHTMLReferences(t *testing.T) {
+	pattern := regexp.MustCompile(`\$\(params\.\w+\)`)
+	resolve := func(ref string) Reference {
+		return Reference{Text: strings.ToUpper(ref), Title: "value of " + ref}
+	}
+
+	var sb strings.Builder
+	err := FormatHTML(
+		&sb,
+		"#!/bin/bash\necho $(params.a) \"$(params.b)\"\n# $(params.c)\n",
+		WithLanguage("bash"),
+		WithReferences(pattern, resolve),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := sb.String()
+	for _, ref := range []string{"a", "b", "c"} {
+		want := referenceHTML(Reference{
+			Text:  "$(PARAMS." + strings.ToUpper(ref) + ")",
+			Title: "value of $(params." + ref + ")",
+		})
+		if !strings.Contains(out, want) {
+			t.Errorf("reference %s not rendered as %s in:\n%s", ref, want, out)
+		}
+	}
+	if strings.Contains(out, "\x00") {
+		t.Errorf("placeholders left in:\n%s", out)
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		opts   []Option
+		want   string
+	}{
+		{"bash shebang", "#!/bin/bash\nls", nil, "Bash"},
+		{"env shebang", "#!/usr/bin/env python3\nprint(1)", nil, "Python"},
+		{"env flags", "#!/usr/bin/env -S node --no-warnings\n1", nil, "JavaScript"},
+		{"versioned", "#!/usr/local/bin/python3.11\n1", nil, "Python"},
+		{"image", "x = 1", []Option{WithImage("docker.io/library/python:3.11@sha256:abc")}, "Python"},
+		{"golang image", "echo", []Option{WithImage("golang:1.20")}, "Go"},
+		{"shebang over image", "#!/bin/sh\necho", []Option{WithImage("golang:1.20")}, "Bash"},
+		{"explicit", "#!/bin/sh\necho", []Option{WithLanguage("python")}, "Python"},
+		{"unknown explicit", "#!/bin/sh\necho", []Option{WithLanguage("nope")}, "Bash"},
+	}
+	for _, tt := range tests {
+		if got := Language(tt.script, tt.opts...); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package tekton
 
 import (
+	"strconv"
+
 	"golang.org/x/exp/slices"
 
 	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/syntax"
 	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 )
 
@@ -13,7 +16,18 @@ func (c *Context) BindTemplateData(td *model.TemplateData) error {
 	// TODO: use echo Bind() for param extraction
 	// TODO: maybe run this on the middleware when all routes use template data
 	td.Namespaces = c.opts.namespaces
+	td.SyntaxStyle = c.opts.syntaxStyle
+	if cookie, err := c.Cookie(model.SyntaxStyleCookie); err == nil && syntax.HasStyle(cookie.Value) {
+		td.SyntaxStyle = cookie.Value
+	}
+	td.Theme = model.DarkTheme
+	if cookie, err := c.Cookie(model.ThemeCookie); err == nil && cookie.Value == model.LightTheme {
+		td.Theme = model.LightTheme
+	}
+	td.GitWebURL = c.opts.gitWebURL
 
+	// the right run of comparisons may live in another namespace
+	rightNamespace := ""
 	for _, pn := range c.ParamNames() {
 		switch pn {
 		case "namespace":
@@ -49,10 +63,18 @@ func (c *Context) BindTemplateData(td *model.TemplateData) error {
 			if tr != nil && len(td.TaskRuns) == 0 {
 				td.TaskRuns = append(td.TaskRuns, tr)
 			}
+		case "left":
+			td.Left = c.getRun(td.Namespace, td.Resource, c.Param(pn))
+		case "rightNamespace":
+			rightNamespace = c.Param(pn)
+		case "right":
+			td.Right = c.getRun(rightNamespace, td.Resource, c.Param(pn))
 		case "step":
 			td.Step = c.Param(pn)
 		case "tab":
 			td.Tab = c.Param(pn)
+		case "view":
+			td.View = c.Param(pn)
 		}
 	}
 
@@ -62,22 +84,76 @@ func (c *Context) BindTemplateData(td *model.TemplateData) error {
 			td.Namespace = c.QueryParam(pn)
 		case "step":
 			td.Step = c.QueryParam(pn)
+		case "container":
+			td.ContainerKind = c.QueryParam(pn)
 		case "task":
 			td.TaskRun = c.GetTaskRun(td.Namespace, c.QueryParam(pn))
-			td.TaskRuns = []*pipelinev1beta1.TaskRun{td.TaskRun}
+			if td.TaskRun != nil {
+				td.TaskRuns = []*pipelinev1beta1.TaskRun{td.TaskRun}
+			}
 		case "pipelineRun":
 			prName := c.QueryParam(pn)
 			td.PipelineRun = c.GetPipelineRun(td.Namespace, prName)
 			td.TaskRuns = c.GetPipelineTaskRuns(td.Namespace, prName)
+		case "attempt":
+			// invalid attempts fall back to the latest one
+			td.Attempt, _ = strconv.Atoi(c.QueryParam(pn))
+		case "manifest":
+			if m := c.QueryParam(pn); m == model.RunManifest || m == model.SpecManifest {
+				td.Manifest = m
+			}
+		case "script":
+			if s := c.QueryParam(pn); s == model.RawScript || s == model.ResolvedScript {
+				td.Script = s
+			}
+		case "language":
+			td.Language = c.QueryParam(pn)
+		case "format":
+			if f := c.QueryParam(pn); f == model.YAMLFormat || f == model.JSONFormat {
+				td.Format = f
+			}
 		}
 	}
 
-	// auto select first taskRun / step
+	// auto select first taskRun / step, taskRuns which have not started
+	// yet have none
 	if td.Step == "" && len(td.TaskRuns) > 0 {
 		if td.TaskRun == nil {
 			td.TaskRun = td.TaskRuns[0]
 		}
-		td.Step = td.TaskRuns[0].Status.TaskSpec.Steps[0].Name
+		if td.TaskRun != nil {
+			if steps := td.StatusOf(td.TaskRun).Steps; len(steps) > 0 {
+				td.Step = steps[0].Name
+			}
+		}
+	}
+
+	if td.ContainerKind == "" {
+		td.ContainerKind = model.StepContainer
+	}
+	if td.Manifest == "" {
+		td.Manifest = model.RunManifest
+	}
+	if td.Format == "" {
+		td.Format = model.YAMLFormat
+	}
+	if td.Script == "" {
+		td.Script = model.RawScript
+	}
+
+	if td.TaskRun != nil {
+		td.Pod = c.GetPod(td.Namespace, td.StatusOf(td.TaskRun).PodName)
+		if td.PipelineRun == nil {
+			td.ParentPipelineRun = c.GetParentPipelineRun(td.TaskRun)
+		}
+	}
+
+	switch {
+	case td.PipelineRun != nil:
+		td.ChildPipelineRuns = c.GetChildPipelineRuns(td.PipelineRun)
+		td.Ancestry = c.GetPipelineRunAncestry(td.PipelineRun)
+	case td.ParentPipelineRun != nil:
+		td.Ancestry = c.GetPipelineRunAncestry(td.ParentPipelineRun)
 	}
 
 	if log := c.Log.V(4); log.Enabled() {
@@ -110,3 +186,23 @@ func (c *Context) BindTemplateData(td *model.TemplateData) error {
 
 	return nil
 }
+
+// getRun resolves a run of the given resource, or returns nil if it
+// does not exist.
+func (c *Context) getRun(namespace, resource, name string) *model.Run {
+	if resource == "taskruns" {
+		tr := c.GetTaskRun(namespace, name)
+		if tr == nil {
+			return nil
+		}
+		return &model.Run{TaskRuns: []*pipelinev1beta1.TaskRun{tr}}
+	}
+	pr := c.GetPipelineRun(namespace, name)
+	if pr == nil {
+		return nil
+	}
+	return &model.Run{
+		PipelineRun: pr,
+		TaskRuns:    c.GetPipelineTaskRuns(namespace, name),
+	}
+}
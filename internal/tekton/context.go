@@ -1,10 +1,15 @@
 package tekton
 
 import (
+	"text/template"
+
 	"github.com/cezarguimaraes/tkn-dash/pkg/cache"
 	"github.com/go-logr/logr"
 	"github.com/labstack/echo/v4"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Context struct {
@@ -18,8 +23,11 @@ type Context struct {
 }
 
 type mwOpts struct {
-	namespaces []string
-	log        logr.Logger
+	namespaces  []string
+	log         logr.Logger
+	pods        cache.Store
+	syntaxStyle string
+	gitWebURL   *template.Template
 }
 
 type Option func(*mwOpts)
@@ -36,6 +44,29 @@ func WithLogger(log logr.Logger) Option {
 	}
 }
 
+// WithPodStore allows resolving the pods backing taskRuns.
+func WithPodStore(pods cache.Store) Option {
+	return func(o *mwOpts) {
+		o.pods = pods
+	}
+}
+
+// WithSyntaxStyle sets the chroma style code snippets are
+// highlighted with.
+func WithSyntaxStyle(style string) Option {
+	return func(o *mwOpts) {
+		o.syntaxStyle = style
+	}
+}
+
+// WithGitWebURL sets the template of the web URL git sources of
+// resolved tasks and pipelines link to.
+func WithGitWebURL(tmpl *template.Template) Option {
+	return func(o *mwOpts) {
+		o.gitWebURL = tmpl
+	}
+}
+
 // TODO: remove namespaces param
 
 func NewMiddleware(pr, tr cache.Store, opts ...Option) echo.MiddlewareFunc {
@@ -87,6 +118,129 @@ func (c *Context) GetPipelineRun(namespace, name string) *pipelinev1beta1.Pipeli
 	return pr.(*pipelinev1beta1.PipelineRun)
 }
 
+// GetPod returns the pod with the given name, or nil when it does not
+// exist, e.g. because it was garbage collected, or no pod store is
+// available.
+func (c *Context) GetPod(namespace, name string) *corev1.Pod {
+	if c.opts.pods == nil || name == "" {
+		return nil
+	}
+	pod, err := c.opts.pods.Get(namespace, name)
+	if err != nil {
+		return nil
+	}
+	return pod.(*corev1.Pod)
+}
+
+// GetParentPipelineRun returns the pipelineRun owning obj, either
+// directly or through the custom run, such as the ones of
+// pipelines-in-pipelines, which started it. Failing that, it is found
+// through the pipelineRun label set by tekton. It returns nil if obj
+// has no parent or the parent no longer exists.
+func (c *Context) GetParentPipelineRun(obj metav1.Object) *pipelinev1beta1.PipelineRun {
+	for _, ref := range obj.GetOwnerReferences() {
+		switch {
+		case ref.Kind == pipeline.PipelineRunControllerName:
+			if pr := c.GetPipelineRun(obj.GetNamespace(), ref.Name); pr != nil {
+				return pr
+			}
+		case isCustomRun(ref.Kind):
+			if pr := c.getCustomRunParent(obj.GetNamespace(), ref.Name); pr != nil {
+				return pr
+			}
+		}
+	}
+	if name := obj.GetLabels()[pipeline.PipelineRunLabelKey]; name != "" && name != obj.GetName() {
+		return c.GetPipelineRun(obj.GetNamespace(), name)
+	}
+	return nil
+}
+
+// getCustomRunParent returns the pipelineRun whose child references name
+// the custom run with the given name.
+func (c *Context) getCustomRunParent(namespace, name string) *pipelinev1beta1.PipelineRun {
+	for _, pr := range c.listPipelineRuns(namespace) {
+		for _, cr := range pr.Status.ChildReferences {
+			if isCustomRun(cr.Kind) && cr.Name == name {
+				return pr
+			}
+		}
+	}
+	return nil
+}
+
+// listPipelineRuns returns all pipelineRuns of namespace.
+func (c *Context) listPipelineRuns(namespace string) []*pipelinev1beta1.PipelineRun {
+	items, _, err := c.pr.Search(&cache.SearchOptions{
+		Limit:     -1,
+		Namespace: &namespace,
+	})
+	if err != nil {
+		c.Log.V(4).Info("failed to list pipelineRuns", "error", err)
+		return nil
+	}
+	prs := make([]*pipelinev1beta1.PipelineRun, 0, len(items))
+	for _, it := range items {
+		prs = append(prs, it.(*pipelinev1beta1.PipelineRun))
+	}
+	return prs
+}
+
+// isCustomRun tells whether kind is the kind of custom runs, v1alpha1
+// Runs included.
+func isCustomRun(kind string) bool {
+	return kind == "Run" || kind == "CustomRun"
+}
+
+// GetPipelineRunAncestry returns the pipelineRuns pr is nested in,
+// outermost first.
+func (c *Context) GetPipelineRunAncestry(pr *pipelinev1beta1.PipelineRun) []*pipelinev1beta1.PipelineRun {
+	var ancestry []*pipelinev1beta1.PipelineRun
+	seen := map[string]bool{pr.GetName(): true}
+	for parent := c.GetParentPipelineRun(pr); parent != nil; parent = c.GetParentPipelineRun(parent) {
+		if seen[parent.GetName()] {
+			break
+		}
+		seen[parent.GetName()] = true
+		ancestry = append([]*pipelinev1beta1.PipelineRun{parent}, ancestry...)
+	}
+	return ancestry
+}
+
+// GetChildPipelineRuns returns the pipelineRuns started by pr, either
+// directly or through custom runs, such as the ones of
+// pipelines-in-pipelines, which own the pipelineRuns they start.
+func (c *Context) GetChildPipelineRuns(pr *pipelinev1beta1.PipelineRun) []*pipelinev1beta1.PipelineRun {
+	var prs []*pipelinev1beta1.PipelineRun
+	var customRuns []string
+	for _, cr := range pr.Status.ChildReferences {
+		switch {
+		case cr.Kind == pipeline.PipelineRunControllerName:
+			if child := c.GetPipelineRun(pr.GetNamespace(), cr.Name); child != nil {
+				prs = append(prs, child)
+			}
+		case isCustomRun(cr.Kind):
+			customRuns = append(customRuns, cr.Name)
+		}
+	}
+	if len(customRuns) == 0 {
+		return prs
+	}
+
+	owned := map[string][]*pipelinev1beta1.PipelineRun{}
+	for _, child := range c.listPipelineRuns(pr.GetNamespace()) {
+		for _, ref := range child.GetOwnerReferences() {
+			if isCustomRun(ref.Kind) {
+				owned[ref.Name] = append(owned[ref.Name], child)
+			}
+		}
+	}
+	for _, name := range customRuns {
+		prs = append(prs, owned[name]...)
+	}
+	return prs
+}
+
 func (c *Context) GetPipelineTaskRuns(namespace, name string) []*pipelinev1beta1.TaskRun {
 	pr := c.GetPipelineRun(namespace, name)
 	if pr == nil {
@@ -94,7 +248,12 @@ func (c *Context) GetPipelineTaskRuns(namespace, name string) []*pipelinev1beta1
 	}
 	var trs []*pipelinev1beta1.TaskRun
 	for _, cr := range pr.Status.ChildReferences {
-		trs = append(trs, c.GetTaskRun(namespace, cr.Name))
+		if cr.Kind != "" && cr.Kind != pipeline.TaskRunControllerName {
+			continue
+		}
+		if tr := c.GetTaskRun(namespace, cr.Name); tr != nil {
+			trs = append(trs, tr)
+		}
 	}
 	// support for < v0.45
 	if trMap := pr.Status.TaskRuns; len(trs) == 0 && len(trMap) > 0 {
@@ -7,22 +7,27 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"text/template"
 	"time"
 
 	"github.com/cezarguimaraes/tkn-dash/internal/components"
 	"github.com/cezarguimaraes/tkn-dash/internal/handlers"
 	"github.com/cezarguimaraes/tkn-dash/internal/loader"
 	"github.com/cezarguimaraes/tkn-dash/internal/model"
+	"github.com/cezarguimaraes/tkn-dash/internal/syntax"
 	"github.com/cezarguimaraes/tkn-dash/internal/tekton"
 	"github.com/cezarguimaraes/tkn-dash/internal/tools"
 	"github.com/cezarguimaraes/tkn-dash/pkg/cache"
+	"github.com/cezarguimaraes/tkn-dash/pkg/logs"
 	"github.com/go-logr/logr"
 	"github.com/pkg/browser"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	tektoncs "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -34,9 +39,14 @@ var static embed.FS
 
 var (
 	kubeconfig  = flag.String("kubeconfig", "", "(optional) path to kubeconfig")
-	chromaStyle = flag.String("syntax-style", "github-dark", "a valid style name from https://xyproto.github.io/splash/docs/")
+	chromaStyle = flag.String("syntax-style", "github-dark", "default syntax style users may override, a valid style name from https://xyproto.github.io/splash/docs/")
 	addr        = flag.String("addr", ":", "[address]:port to listen on")
 	openBrowser = flag.Bool("browser", false, "whether to try and open a browser to the dashboard")
+	logDir      = flag.String("log-dir", "", "(optional) directory of archived logs, stored as <namespace>/<pod>/<container>.log")
+	lokiURL     = flag.String("loki-url", "", "(optional) base URL of a Loki compatible API to query archived logs from")
+	lokiSel     = flag.String("loki-selector", logs.DefaultLokiSelector, "LogQL stream selector template used to query Loki")
+	cacheSize   = flag.Int64("fragment-cache-mb", 64, "megabytes of rendered scripts, manifests and logs to cache, 0 disables caching")
+	gitWebURL   = flag.String("git-web-url", "{{.Repo}}/blob/{{.Revision}}/{{.Path}}", "template of the web URL git sources of resolved tasks and pipelines link to, from their .Repo, .Revision and .Path. Empty disables links")
 )
 
 func main() {
@@ -46,7 +56,7 @@ func main() {
 
 	log := klog.NewKlogr()
 
-	var trs, prs cache.Store
+	var trs, prs, pods cache.Store
 	var kubeclientset *clientset.Clientset
 
 	if args := flag.Args(); len(args) > 0 {
@@ -81,6 +91,17 @@ func main() {
 		var stopFn func()
 		trs, prs, stopFn = initializeStores(log, tcs)
 		defer stopFn()
+
+		// pods are only looked up for the details of taskRuns, so
+		// their informer is not waited for
+		var podStopFn func()
+		pods, podStopFn = cache.NewSharedInformerCache(
+			kubeclientset.CoreV1().RESTClient(),
+			"pods",
+			&corev1.Pod{},
+			cache.WithLabelSelector(pipeline.TaskRunLabelKey),
+		)
+		defer podStopFn()
 	}
 
 	nsLister := tools.NamespaceListerFromStore(trs, prs)
@@ -90,11 +111,49 @@ func main() {
 		klog.FlushAndExit(10*time.Second, 1)
 	}
 
-	tknMiddleware := tekton.NewMiddleware(
-		prs, trs,
+	// providers are tried in order, so live pods take precedence
+	// over archives
+	var logProviders []logs.Provider
+	if kubeclientset != nil {
+		logProviders = append(logProviders, logs.FromClientset(kubeclientset))
+	}
+	if *logDir != "" {
+		logProviders = append(logProviders, logs.FromDirectory(*logDir))
+	}
+	if *lokiURL != "" {
+		selector, err := logs.NewLokiSelector(*lokiSel)
+		if err != nil {
+			log.Error(err, "error parsing loki selector template")
+			klog.FlushAndExit(10*time.Second, 1)
+		}
+		logProviders = append(logProviders, logs.FromLoki(
+			*lokiURL,
+			logs.WithLokiSelector(selector),
+		))
+	}
+
+	if !syntax.HasStyle(*chromaStyle) {
+		log.Error(nil, "unknown syntax style", "style", *chromaStyle)
+		klog.FlushAndExit(10*time.Second, 1)
+	}
+
+	tknOpts := []tekton.Option{
 		tekton.WithNamespaces(namespaces),
 		tekton.WithLogger(log),
-	)
+		tekton.WithSyntaxStyle(*chromaStyle),
+	}
+	if *gitWebURL != "" {
+		tmpl, err := template.New("git-web-url").Parse(*gitWebURL)
+		if err != nil {
+			log.Error(err, "error parsing git web URL template")
+			klog.FlushAndExit(10*time.Second, 1)
+		}
+		tknOpts = append(tknOpts, tekton.WithGitWebURL(tmpl))
+	}
+	if pods != nil {
+		tknOpts = append(tknOpts, tekton.WithPodStore(pods))
+	}
+	tknMiddleware := tekton.NewMiddleware(prs, trs, tknOpts...)
 
 	e := echo.New()
 
@@ -190,6 +249,16 @@ func main() {
 			name:      "list-w-pipe-details-tab",
 			component: components.Shell(components.Explorer),
 		},
+		{
+			route:     "/:namespace/:resource/:pipelineRun/taskruns/:taskRun",
+			name:      "list-w-pipe-task",
+			component: components.Shell(components.Explorer),
+		},
+		{
+			route:     "/:namespace/:resource/:pipelineRun/view/:view",
+			name:      "list-w-pipe-view",
+			component: components.Shell(components.Explorer),
+		},
 		{
 			route:     "/:namespace/:resource/:name/details",
 			name:      "details",
@@ -200,6 +269,36 @@ func main() {
 			name:      "details-w-step",
 			component: components.TaskRunDetails(true),
 		},
+		{
+			route:     "/:namespace/details/:taskRun",
+			name:      "details-wo-step",
+			component: components.TaskRunDetails(true),
+		},
+		{
+			route:     "/stepdetails/:namespace/:taskRun/step/:step",
+			name:      "step-details",
+			component: components.StepDetails,
+		},
+		{
+			route:     "/summary/:namespace/:pipelineRun",
+			name:      "pipeline-summary",
+			component: components.PipelineRunSummary,
+		},
+		{
+			route:     "/children/:namespace/:pipelineRun",
+			name:      "pipeline-children",
+			component: components.PipelineChildren,
+		},
+		{
+			route:     "/graph/:namespace/:pipelineRun",
+			name:      "pipeline-graph",
+			component: components.PipelineGraph,
+		},
+		{
+			route:     "/timeline/:namespace/:pipelineRun",
+			name:      "pipeline-timeline",
+			component: components.PipelineTimeline,
+		},
 	}
 
 	for _, ct := range componentRoutes {
@@ -209,18 +308,52 @@ func main() {
 		).Name = ct.name
 	}
 
+	lp := logs.Chain(logProviders...)
+
+	var fc *handlers.FragmentCache
+	if *cacheSize > 0 {
+		fc = handlers.NewFragmentCache(*cacheSize << 20)
+		e.GET("/debug/fragment-cache",
+			handlers.FragmentStats(fc),
+		).Name = "fragment-cache-stats"
+	}
+
 	e.GET("/log/:namespace/:taskRun/step/:step",
-		handlers.StepLog(kubeclientset),
+		handlers.StepLog(lp, fc),
 	).Name = "log"
 
+	e.GET("/pipelinelog/:namespace/:pipelineRun",
+		handlers.PipelineLog(lp),
+	).Name = "pipeline-log"
+
 	e.GET("/script/:namespace/:taskRun/step/:step",
-		handlers.StepScript(*chromaStyle),
+		handlers.StepScript(fc),
 	).Name = "script"
 
 	e.GET("/manifest/:namespace/:taskRun/step/:step",
-		handlers.Manifest(*chromaStyle),
+		handlers.Manifest(fc),
 	).Name = "manifest"
 
+	e.GET("/pipelinemanifest/:namespace/:pipelineRun",
+		handlers.PipelineManifest(fc),
+	).Name = "pipeline-manifest"
+
+	e.GET("/:namespace/compare/:resource/:left/:rightNamespace/:right",
+		handlers.Compare(),
+	).Name = "compare"
+
+	e.GET("/compare/:resource",
+		handlers.CompareSelection(),
+	).Name = "compare-selection"
+
+	e.GET("/syntax/:style",
+		handlers.SyntaxStylesheet(),
+	).Name = "syntax-css"
+
+	e.GET("/preferences",
+		handlers.Preferences(),
+	).Name = "preferences"
+
 	e.GET("/:resource/items",
 		handlers.Search(
 			components.ExplorerListItems,
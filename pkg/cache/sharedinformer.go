@@ -17,6 +17,19 @@ type SharedInformerCache struct {
 	closeCh chan struct{}
 }
 
+type informerOpts struct {
+	labelSelector string
+}
+
+type InformerOption func(*informerOpts)
+
+// WithLabelSelector restricts the informer to objects matching selector.
+func WithLabelSelector(selector string) InformerOption {
+	return func(o *informerOpts) {
+		o.labelSelector = selector
+	}
+}
+
 // TODO: warning whenever there is a List() before
 // the shared informer HasSynced()
 
@@ -24,12 +37,20 @@ func NewSharedInformerCache(
 	getter cache.Getter,
 	resource string,
 	exampleObject runtime.Object,
+	opts ...InformerOption,
 ) (*SharedInformerCache, func()) {
-	lw := cache.NewListWatchFromClient(
+	options := &informerOpts{}
+	for _, o := range opts {
+		o(options)
+	}
+	lw := cache.NewFilteredListWatchFromClient(
 		getter,
 		resource,
 		"",
-		fields.Everything(),
+		func(lo *metav1.ListOptions) {
+			lo.FieldSelector = fields.Everything().String()
+			lo.LabelSelector = options.labelSelector
+		},
 	)
 
 	si := cache.NewSharedInformer(lw, exampleObject, 5*time.Minute)
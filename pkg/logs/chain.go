@@ -0,0 +1,37 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNoProviders is returned by an empty chain.
+var ErrNoProviders = errors.New("no log providers configured")
+
+type chain struct {
+	providers []Provider
+}
+
+var _ Provider = &chain{}
+
+// Chain returns a Provider which tries each of the given providers
+// in order, falling back to the next one whenever a lookup fails.
+func Chain(providers ...Provider) *chain {
+	return &chain{providers}
+}
+
+func (c *chain) Logs(ctx context.Context, ref ContainerRef, opts *Options) (io.ReadCloser, error) {
+	if len(c.providers) == 0 {
+		return nil, ErrNoProviders
+	}
+	var errs []error
+	for _, p := range c.providers {
+		rc, err := p.Logs(ctx, ref, opts)
+		if err == nil {
+			return rc, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
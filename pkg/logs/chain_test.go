@@ -0,0 +1,78 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type failingProvider struct{}
+
+func (failingProvider) Logs(context.Context, ContainerRef, *Options) (io.ReadCloser, error) {
+	return nil, errors.New("pod not found")
+}
+
+func TestChainFallsBackToDirectory(t *testing.T) {
+	root := t.TempDir()
+	podDir := filepath.Join(root, "default", "pod-1")
+	if err := os.MkdirAll(podDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "2023-08-01T10:00:00.000000001Z hello\n2023-08-01T10:00:01Z world\n"
+	if err := os.WriteFile(filepath.Join(podDir, "step-build.log"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	lp := Chain(failingProvider{}, FromDirectory(root))
+	ref := ContainerRef{Namespace: "default", Pod: "pod-1", Container: "step-build"}
+
+	tests := []struct {
+		opts *Options
+		want string
+	}{
+		{&Options{}, "hello\nworld\n"},
+		{&Options{Timestamps: true}, content},
+	}
+	for _, tt := range tests {
+		rc, err := lp.Logs(context.Background(), ref, tt.opts)
+		if err != nil {
+			t.Fatalf("Logs(%+v) got err %v, want nil", tt.opts, err)
+		}
+		got, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("Logs(%+v) got %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestChainNoProviders(t *testing.T) {
+	_, err := Chain().Logs(context.Background(), ContainerRef{}, &Options{})
+	if !errors.Is(err, ErrNoProviders) {
+		t.Errorf("Chain().Logs() got err %v, want %v", err, ErrNoProviders)
+	}
+}
+
+func TestSplitTimestamp(t *testing.T) {
+	tests := []struct {
+		line, content string
+		ok            bool
+	}{
+		{"2023-08-01T10:00:00.123456789Z + make build", "+ make build", true},
+		{"2023-08-01T10:00:00Z", "", true},
+		{"no timestamp here", "no timestamp here", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		_, content, ok := SplitTimestamp(tt.line)
+		if content != tt.content || ok != tt.ok {
+			t.Errorf("SplitTimestamp(%q) = (_, %q, %v), want (_, %q, %v)", tt.line, content, ok, tt.content, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package logs
+
+import (
+	"context"
+	"io"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type clientsetProvider struct {
+	cs kubernetes.Interface
+}
+
+var _ Provider = &clientsetProvider{}
+
+// FromClientset returns a Provider which streams logs from live pods
+// through the kubernetes API.
+func FromClientset(cs kubernetes.Interface) *clientsetProvider {
+	return &clientsetProvider{cs}
+}
+
+func (p *clientsetProvider) Logs(ctx context.Context, ref ContainerRef, opts *Options) (io.ReadCloser, error) {
+	return p.cs.CoreV1().Pods(ref.Namespace).GetLogs(
+		ref.Pod,
+		&v1.PodLogOptions{
+			Container:  ref.Container,
+			Timestamps: opts.Timestamps,
+		},
+	).Stream(ctx)
+}
@@ -0,0 +1,52 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type directoryProvider struct {
+	root string
+}
+
+var _ Provider = &directoryProvider{}
+
+// FromDirectory returns a Provider which reads archived logs stored as
+// <root>/<namespace>/<pod>/<container>.log
+//
+// Archived lines may or may not be prefixed with timestamps, they are
+// stripped when timestamps were not requested.
+func FromDirectory(root string) *directoryProvider {
+	return &directoryProvider{root}
+}
+
+func (p *directoryProvider) Logs(ctx context.Context, ref ContainerRef, opts *Options) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(
+		p.root,
+		filepath.Clean("/"+ref.Namespace),
+		filepath.Clean("/"+ref.Pod),
+		filepath.Clean("/"+ref.Container+".log"),
+	))
+	if err != nil {
+		return nil, err
+	}
+	if opts.Timestamps {
+		return f, nil
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	sc := NewScanner(f)
+	for sc.Scan() {
+		_, line, _ := SplitTimestamp(sc.Text())
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(&buf), nil
+}
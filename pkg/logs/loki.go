@@ -0,0 +1,234 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	lokiQueryPath = "/loki/api/v1/query_range"
+
+	// DefaultLokiSelector matches the labels set by promtail's
+	// kubernetes-pods scrape configuration.
+	DefaultLokiSelector = `{namespace={{quote .Namespace}}, pod={{quote .Pod}}, container={{quote .Container}}}`
+)
+
+var defaultLokiSelector = template.Must(NewLokiSelector(DefaultLokiSelector))
+
+// NewLokiSelector parses a LogQL stream selector template. Label values
+// must go through its quote function, which renders them as LogQL string
+// literals.
+func NewLokiSelector(text string) (*template.Template, error) {
+	return template.New("loki-selector").
+		Funcs(template.FuncMap{"quote": strconv.Quote}).
+		Parse(text)
+}
+
+type lokiProvider struct {
+	baseURL  string
+	selector *template.Template
+	limit    int
+	maxLines int
+	client   *http.Client
+}
+
+var _ Provider = &lokiProvider{}
+
+type LokiOption func(*lokiProvider)
+
+// WithLokiSelector overrides the LogQL stream selector, a template
+// executed against the requested ContainerRef.
+func WithLokiSelector(selector *template.Template) LokiOption {
+	return func(p *lokiProvider) {
+		p.selector = selector
+	}
+}
+
+// WithLokiLimit sets the maximum amount of lines returned per query.
+// Longer logs are queried in several pages.
+func WithLokiLimit(limit int) LokiOption {
+	return func(p *lokiProvider) {
+		p.limit = limit
+	}
+}
+
+// WithLokiMaxLines sets the maximum amount of lines returned per log,
+// after which it is truncated.
+func WithLokiMaxLines(maxLines int) LokiOption {
+	return func(p *lokiProvider) {
+		p.maxLines = maxLines
+	}
+}
+
+func WithHTTPClient(client *http.Client) LokiOption {
+	return func(p *lokiProvider) {
+		p.client = client
+	}
+}
+
+// FromLoki returns a Provider which queries a Loki compatible
+// query_range API served at baseURL.
+func FromLoki(baseURL string, opts ...LokiOption) *lokiProvider {
+	p := &lokiProvider{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		selector: defaultLokiSelector,
+		limit:    5000,
+		maxLines: 200000,
+		client:   http.DefaultClient,
+	}
+	for _, o := range opts {
+		o(p)
+	}
+	return p
+}
+
+type lokiResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Result []struct {
+			Values [][2]string `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+type lokiEntry struct {
+	ts   int64
+	line string
+}
+
+func (p *lokiProvider) Logs(ctx context.Context, ref ContainerRef, opts *Options) (io.ReadCloser, error) {
+	var query strings.Builder
+	if err := p.selector.Execute(&query, ref); err != nil {
+		return nil, err
+	}
+
+	// pages are queried from the last timestamp of the previous one, as
+	// more entries may share it, so those already seen are skipped
+	var entries []lokiEntry
+	var seen map[lokiEntry]bool
+	start := int64(0)
+	if !opts.Since.IsZero() {
+		start = opts.Since.UnixNano()
+	}
+	truncated := false
+	for {
+		page, err := p.query(ctx, query.String(), start, opts.Until)
+		if err != nil {
+			return nil, err
+		}
+		last := start
+		added := 0
+		for _, e := range page {
+			if seen[e] {
+				continue
+			}
+			entries = append(entries, e)
+			added++
+			if e.ts > last {
+				last = e.ts
+			}
+		}
+		if len(entries) > p.maxLines {
+			entries, truncated = entries[:p.maxLines], true
+			break
+		}
+		if len(page) < p.limit || added == 0 {
+			break
+		}
+		seen = map[lokiEntry]bool{}
+		for _, e := range page {
+			if e.ts == last {
+				seen[e] = true
+			}
+		}
+		start = last
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("no logs found in loki")
+	}
+
+	var buf bytes.Buffer
+	write := func(ts int64, line string) {
+		if opts.Timestamps {
+			buf.WriteString(time.Unix(0, ts).UTC().Format(time.RFC3339Nano))
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(strings.TrimSuffix(line, "\n"))
+		buf.WriteByte('\n')
+	}
+	for _, e := range entries {
+		write(e.ts, e.line)
+	}
+	if truncated {
+		write(entries[len(entries)-1].ts, fmt.Sprintf("[log truncated after %d lines]", p.maxLines))
+	}
+	return io.NopCloser(&buf), nil
+}
+
+// query returns the entries of the streams matching query from start,
+// oldest first, up to the limit of p.
+func (p *lokiProvider) query(ctx context.Context, query string, start int64, until time.Time) ([]lokiEntry, error) {
+	qs := url.Values{}
+	qs.Set("query", query)
+	qs.Set("direction", "forward")
+	qs.Set("limit", strconv.Itoa(p.limit))
+	if start != 0 {
+		qs.Set("start", strconv.FormatInt(start, 10))
+	}
+	if !until.IsZero() {
+		qs.Set("end", strconv.FormatInt(until.UnixNano(), 10))
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		p.baseURL+lokiQueryPath+"?"+qs.Encode(),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("loki query failed with status %d: %s", resp.StatusCode, msg)
+	}
+
+	var out lokiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+
+	// entries of distinct streams are only ordered within their stream
+	var entries []lokiEntry
+	for _, r := range out.Data.Result {
+		for _, v := range r.Values {
+			ts, err := strconv.ParseInt(v[0], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, lokiEntry{ts, v[1]})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].ts < entries[j].ts
+	})
+	return entries, nil
+}
@@ -0,0 +1,86 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeLoki serves lines, one per nanosecond timestamp, in pages as a
+// Loki query_range API would.
+func fakeLoki(t *testing.T, lines []string, queries *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*queries++
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		var values [][2]string
+		for ts := start; ts < len(lines) && len(values) < limit; ts++ {
+			values = append(values, [2]string{strconv.Itoa(ts), lines[ts]})
+		}
+		var out lokiResponse
+		out.Status = "success"
+		out.Data.Result = append(out.Data.Result, struct {
+			Values [][2]string `json:"values"`
+		}{values})
+		if err := json.NewEncoder(w).Encode(out); err != nil {
+			t.Error(err)
+		}
+	}))
+}
+
+func TestLokiPaginates(t *testing.T) {
+	lines := []string{"a", "b", "c", "d", "e", "f", "g"}
+	tests := []struct {
+		name     string
+		maxLines int
+		want     string
+	}{
+		{"all pages", 100, "a\nb\nc\nd\ne\nf\ng\n"},
+		{"truncated", 4, "a\nb\nc\nd\n[log truncated after 4 lines]\n"},
+	}
+	for _, tt := range tests {
+		var queries int
+		srv := fakeLoki(t, lines, &queries)
+		lp := FromLoki(srv.URL, WithLokiLimit(3), WithLokiMaxLines(tt.maxLines))
+		rc, err := lp.Logs(context.Background(), ContainerRef{}, &Options{})
+		if err != nil {
+			t.Fatalf("%s: Logs() got err %v, want nil", tt.name, err)
+		}
+		got, _ := io.ReadAll(rc)
+		rc.Close()
+		srv.Close()
+		if string(got) != tt.want {
+			t.Errorf("%s: Logs() got %q, want %q", tt.name, got, tt.want)
+		}
+		if queries < 2 {
+			t.Errorf("%s: queried %d pages, want several", tt.name, queries)
+		}
+	}
+}
+
+func TestLokiNoLogs(t *testing.T) {
+	var queries int
+	srv := fakeLoki(t, nil, &queries)
+	defer srv.Close()
+	_, err := FromLoki(srv.URL).Logs(context.Background(), ContainerRef{}, &Options{})
+	if err == nil || !strings.Contains(err.Error(), "no logs") {
+		t.Errorf("Logs() got err %v, want no logs found", err)
+	}
+}
+
+func TestLokiSelectorQuotes(t *testing.T) {
+	var sb strings.Builder
+	ref := ContainerRef{Namespace: "ns", Pod: `pod"} |= "x`, Container: `step\build`}
+	if err := defaultLokiSelector.Execute(&sb, ref); err != nil {
+		t.Fatal(err)
+	}
+	want := `{namespace="ns", pod="pod\"} |= \"x", container="step\\build"}`
+	if sb.String() != want {
+		t.Errorf("selector got %s, want %s", sb.String(), want)
+	}
+}
@@ -0,0 +1,35 @@
+package logs
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxLineLength bounds the size of a single log line when scanning.
+const maxLineLength = 1024 * 1024
+
+// NewScanner returns a scanner splitting logs read from r into lines of
+// up to maxLineLength bytes.
+func NewScanner(r io.Reader) *bufio.Scanner {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, maxLineLength)
+	return sc
+}
+
+// SplitTimestamp splits a line prefixed by an RFC3339 timestamp,
+// as written by the kubelet when timestamps are requested, into
+// its time and content. ok is false when the line has no timestamp,
+// in which case line is returned untouched.
+func SplitTimestamp(line string) (ts time.Time, content string, ok bool) {
+	prefix, rest, found := strings.Cut(line, " ")
+	if !found {
+		prefix, rest = line, ""
+	}
+	ts, err := time.Parse(time.RFC3339Nano, prefix)
+	if err != nil {
+		return time.Time{}, line, false
+	}
+	return ts, rest, true
+}
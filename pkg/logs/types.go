@@ -0,0 +1,32 @@
+package logs
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Provider retrieves the logs of a single container. Implementations
+// are free to look them up anywhere, which allows logs to outlive the
+// pods that produced them.
+type Provider interface {
+	Logs(ctx context.Context, ref ContainerRef, opts *Options) (io.ReadCloser, error)
+}
+
+// ContainerRef identifies the container whose logs are requested.
+type ContainerRef struct {
+	Namespace string
+	Pod       string
+	Container string
+}
+
+type Options struct {
+	// Timestamps prefixes every line with its RFC3339 timestamp,
+	// the same way `kubectl logs --timestamps` does.
+	Timestamps bool
+
+	// Since and Until bound the time window in which logs are looked up.
+	// Only used by providers that query by time, zero values
+	// mean unbounded.
+	Since, Until time.Time
+}
@@ -0,0 +1,118 @@
+// Package lru implements a size bounded cache evicting the least
+// recently used entries first.
+package lru
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache is a least recently used cache, safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int64
+	size     int64
+	sizeOf   func(V) int64
+	ll       *list.List
+	items    map[K]*list.Element
+	stats    Stats
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	size  int64
+}
+
+// Stats counts the lookups and evictions of a cache.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// HitRate is the ratio of lookups which were hits, 0 before any lookup.
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// New returns a cache holding entries up to a total size of capacity,
+// as measured by sizeOf. A nil sizeOf counts every entry as 1, bounding
+// the number of entries instead.
+func New[K comparable, V any](capacity int64, sizeOf func(V) int64) *Cache[K, V] {
+	if sizeOf == nil {
+		sizeOf = func(V) int64 { return 1 }
+	}
+	return &Cache[K, V]{
+		capacity: capacity,
+		sizeOf:   sizeOf,
+		ll:       list.New(),
+		items:    map[K]*list.Element{},
+	}
+}
+
+// Get returns the value cached for key and marks it as recently used.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	c.stats.Hits++
+	c.ll.MoveToFront(el)
+	return el.Value.(*entry[K, V]).value, true
+}
+
+// Add caches value for key, evicting the least recently used entries
+// until it fits. Values larger than the whole cache are not cached.
+func (c *Cache[K, V]) Add(key K, value V) {
+	size := c.sizeOf(value)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+	if size > c.capacity {
+		return
+	}
+	c.items[key] = c.ll.PushFront(&entry[K, V]{key, value, size})
+	c.size += size
+	for c.size > c.capacity {
+		c.remove(c.ll.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *Cache[K, V]) remove(el *list.Element) {
+	e := c.ll.Remove(el).(*entry[K, V])
+	delete(c.items, e.key)
+	c.size -= e.size
+}
+
+// Len returns the number of cached entries.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Size returns the total size of the cached entries.
+func (c *Cache[K, V]) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Stats returns the lookups and evictions counted so far.
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
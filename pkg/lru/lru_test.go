@@ -0,0 +1,64 @@
+package lru
+
+import "testing"
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[string, int](2, nil)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = (%v, %v), want (1, true)", v, ok)
+	}
+	// b is now the least recently used
+	c.Add("c", 3)
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Get(b) found an evicted entry")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if v, ok := c.Get(key); !ok || v != want {
+			t.Errorf("Get(%s) = (%v, %v), want (%v, true)", key, v, ok, want)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+
+	c.Add("a", 10)
+	if v, _ := c.Get("a"); v != 10 {
+		t.Errorf("Get(a) = %v after update, want 10", v)
+	}
+
+	want := Stats{Hits: 4, Misses: 1, Evictions: 1}
+	if got := c.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if got := c.Stats().HitRate(); got != 0.8 {
+		t.Errorf("HitRate() = %v, want 0.8", got)
+	}
+}
+
+func TestCacheBoundsSize(t *testing.T) {
+	c := New[string, string](10, func(v string) int64 { return int64(len(v)) })
+	c.Add("a", "aaaa")
+	c.Add("b", "bbbb")
+	c.Add("c", "cccc")
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Get(a) found an entry evicted to fit c")
+	}
+	if c.Size() != 8 {
+		t.Errorf("Size() = %d, want 8", c.Size())
+	}
+
+	c.Add("b", "bb")
+	if c.Size() != 6 {
+		t.Errorf("Size() = %d after shrinking b, want 6", c.Size())
+	}
+
+	c.Add("huge", "hhhhhhhhhhhh")
+	if _, ok := c.Get("huge"); ok {
+		t.Errorf("Get(huge) found an entry larger than the cache")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+}